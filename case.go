@@ -38,6 +38,11 @@ func (b *ZCaseBuilder) Else(then interface{}) *ZCaseBuilder {
 	return b
 }
 
+// Clone returns a deep copy of the builder.
+func (b *ZCaseBuilder) Clone() *ZCaseBuilder {
+	return clone(b).(*ZCaseBuilder)
+}
+
 // C implements Expression interface.
 func (b *ZCaseBuilder) C(aliasName ...string) Column {
 	return columnExpr(b, aliasName...)
@@ -59,17 +64,22 @@ func (b *ZCaseBuilder) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
 		buf = append(buf, ' ')
 		buf = b.Base.WriteExpression(ctx, buf)
 	}
+	ctx.Indent++
 	for _, wt := range b.WhenThen {
-		buf = append(buf, " WHEN "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "WHEN "...)
 		buf = wt[0].WriteExpression(ctx, buf)
 		buf = append(buf, " THEN "...)
 		buf = wt[1].WriteExpression(ctx, buf)
 	}
 	if b.ElseThen != nil {
-		buf = append(buf, " ELSE "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "ELSE "...)
 		buf = b.ElseThen.WriteExpression(ctx, buf)
 	}
-	return append(buf, " END"...)
+	ctx.Indent--
+	buf = writeSeparator(ctx, buf)
+	return append(buf, "END"...)
 }
 
 // String implements fmt.Stringer interface.
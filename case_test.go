@@ -108,6 +108,21 @@ func TestCase(t *testing.T) {
 	}
 }
 
+func TestCaseClone(t *testing.T) {
+	for i, test := range caseTests {
+		if r := test.B.Clone().String(); r != test.V {
+			t.Errorf("tests[%d] %s: want %q got %q", i, test.Name, test.V, r)
+		}
+	}
+
+	base := Case(C("age")).When(15, "young")
+	want := base.String()
+	base.Clone().When(44, "middle").Else("old")
+	if got := base.String(); got != want {
+		t.Errorf("the base builder was modified: want %s got %s", want, got)
+	}
+}
+
 func TestCaseOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
@@ -0,0 +1,49 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// The portable types which can be used in Cast.
+// They are mapped to the native types in each dialect.
+var (
+	Integer   = qutil.Type{Kind: qutil.Integer}
+	BigInt    = qutil.Type{Kind: qutil.BigInt}
+	Text      = qutil.Type{Kind: qutil.Text}
+	Boolean   = qutil.Type{Kind: qutil.Boolean}
+	Date      = qutil.Type{Kind: qutil.Date}
+	Timestamp = qutil.Type{Kind: qutil.Timestamp}
+	JSON      = qutil.Type{Kind: qutil.JSON}
+	UUID      = qutil.Type{Kind: qutil.UUID}
+	Bytes     = qutil.Type{Kind: qutil.Bytes}
+)
+
+// Decimal creates the portable type such as "DECIMAL(precision, scale)".
+// If precision is 0, the default precision of the dialect is used.
+func Decimal(precision, scale int) qutil.Type {
+	return qutil.Type{Kind: qutil.Decimal, Size: precision, Scale: scale}
+}
+
+// Varchar creates the portable type such as "VARCHAR(n)".
+// If n is 0, the length is not specified.
+func Varchar(n int) qutil.Type {
+	return qutil.Type{Kind: qutil.Varchar, Size: n}
+}
+
+type castFunc struct {
+	V    interface{}
+	Type qutil.Type
+}
+
+func (f *castFunc) String() string               { return expressionToString(f) }
+func (f *castFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *castFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.Cast(ctx, buf, f.V, f.Type)
+}
+
+// Cast creates Function such as "CAST(v AS t)".
+// The type name and the syntax depend on the dialect,
+// such as "CAST(v AS SIGNED)" for BigInt in MySQL and "CAST(v AS BIGINT)" in PostgreSQL.
+// In the dialects which have no boolean type such as MySQL and SQLite,
+// the cast to Boolean is written as "(v != 0)".
+func Cast(v interface{}, t qutil.Type) Function {
+	return &castFunc{V: v, Type: t}
+}
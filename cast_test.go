@@ -0,0 +1,52 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestCast(t *testing.T) {
+	x := C("x")
+	for i, test := range []struct {
+		Type                            qutil.Type
+		MySQL, PostgreSQL, SQLite, Fake string
+	}{
+		{Integer, "CAST(`x` AS SIGNED)", `CAST("x" AS INTEGER)`, `CAST("x" AS INTEGER)`, `CAST("x" AS INTEGER)`},
+		{BigInt, "CAST(`x` AS SIGNED)", `CAST("x" AS BIGINT)`, `CAST("x" AS INTEGER)`, `CAST("x" AS BIGINT)`},
+		{Decimal(10, 2), "CAST(`x` AS DECIMAL(10, 2))", `CAST("x" AS NUMERIC(10, 2))`, `CAST("x" AS NUMERIC)`, `CAST("x" AS DECIMAL(10, 2))`},
+		{Decimal(0, 0), "CAST(`x` AS DECIMAL)", `CAST("x" AS NUMERIC)`, `CAST("x" AS NUMERIC)`, `CAST("x" AS DECIMAL)`},
+		{Text, "CAST(`x` AS CHAR)", `CAST("x" AS TEXT)`, `CAST("x" AS TEXT)`, `CAST("x" AS TEXT)`},
+		{Varchar(20), "CAST(`x` AS CHAR(20))", `CAST("x" AS VARCHAR(20))`, `CAST("x" AS TEXT)`, `CAST("x" AS VARCHAR(20))`},
+		{Boolean, "(`x` != 0)", `CAST("x" AS BOOLEAN)`, `("x" != 0)`, `CAST("x" AS BOOLEAN)`},
+		{Date, "CAST(`x` AS DATE)", `CAST("x" AS DATE)`, `DATE("x")`, `CAST("x" AS DATE)`},
+		{Timestamp, "CAST(`x` AS DATETIME)", `CAST("x" AS TIMESTAMP)`, `DATETIME("x")`, `CAST("x" AS TIMESTAMP)`},
+		{JSON, "CAST(`x` AS JSON)", `CAST("x" AS JSON)`, `JSON("x")`, `CAST("x" AS JSON)`},
+		{UUID, "CAST(`x` AS CHAR(36))", `CAST("x" AS UUID)`, `CAST("x" AS TEXT)`, `CAST("x" AS UUID)`},
+		{Bytes, "CAST(`x` AS BINARY)", `CAST("x" AS BYTEA)`, `CAST("x" AS BLOB)`, `CAST("x" AS BLOB)`},
+	} {
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{
+			{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}, {SQLite, test.SQLite}, {nil, test.Fake},
+		} {
+			sql, _ := Select().Column(Cast(x, test.Type).C()).SetDialect(d.D).ToSQL()
+			if want := "SELECT " + d.Want; sql != want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, want, sql)
+			}
+		}
+	}
+
+	sql, args := Select().Column(Cast(Add(x, 1).C(), BigInt).C("y")).Where(Eq(Cast(C("s"), Integer), 10)).SetDialect(PostgreSQL).ToSQL()
+	if want := `SELECT CAST("x" + $1 AS BIGINT) AS "y" WHERE CAST("s" AS INTEGER) = $2`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if len(args) != 2 {
+		t.Errorf("want 2 args got %v", args)
+	}
+	if got, want := fmt.Sprint(clone(Cast(x, Varchar(3)))), fmt.Sprint(Cast(x, Varchar(3))); got != want {
+		t.Errorf("clone want %s got %s", want, got)
+	}
+}
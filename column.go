@@ -18,6 +18,13 @@ func columnToString(c Column) string {
 	return toString(buf, ctx.Args)
 }
 
+// cudColumnName returns the name of c which is used in INSERT or UPDATE statements.
+func cudColumnName(c Column) string {
+	buf, ctx := qutil.NewContext(c, 32, 0, nil)
+	ctx.CUD = true
+	return string(c.WriteColumn(ctx, buf))
+}
+
 type columnAlias struct {
 	Column Column
 	Alias  string
@@ -29,8 +36,7 @@ func (c *columnAlias) String() string {
 
 func (c *columnAlias) C(aliasName ...string) Column {
 	if len(aliasName) > 0 {
-		c.Alias = aliasName[0]
-		return c
+		return &columnAlias{Column: c.Column, Alias: aliasName[0]}
 	}
 	return c
 }
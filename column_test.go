@@ -172,6 +172,17 @@ func TestColumn(t *testing.T) {
 	}
 }
 
+func TestColumnAliasImmutable(t *testing.T) {
+	c := C("id", "i")
+	want := fmt.Sprint(c)
+	if r := fmt.Sprint(c.C("j")); r != `"id" AS "j" []` {
+		t.Errorf("want %s got %s", `"id" AS "j" []`, r)
+	}
+	if r := fmt.Sprint(c); r != want {
+		t.Errorf("the original column was modified: want %s got %s", want, r)
+	}
+}
+
 func TestColumnOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
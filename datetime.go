@@ -0,0 +1,129 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// The units which can be used in IntervalOf, DateTrunc, Extract and DateDiff.
+// Millisecond and Microsecond can be used only in IntervalOf,
+// and the others write NULL and Validate reports the error for them.
+const (
+	Year   = qutil.Year
+	Month  = qutil.Month
+	Week   = qutil.Week
+	Day    = qutil.Day
+	Hour   = qutil.Hour
+	Minute = qutil.Minute
+	Second = qutil.Second
+
+	Millisecond = qutil.Millisecond
+	Microsecond = qutil.Microsecond
+)
+
+type currentFunc struct {
+	Time bool
+}
+
+func (f *currentFunc) String() string               { return expressionToString(f) }
+func (f *currentFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *currentFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if f.Time {
+		return append(buf, ctx.Dialect.CurrentTime()...)
+	}
+	return append(buf, ctx.Dialect.CurrentDate()...)
+}
+
+// CurrentDate creates Function "CURRENT_DATE".
+func CurrentDate() Function {
+	return &currentFunc{}
+}
+
+// CurrentTime creates Function which returns the current time of day without the time zone,
+// such as "CURRENT_TIME" in MySQL and "LOCALTIME" in PostgreSQL.
+func CurrentTime() Function {
+	return &currentFunc{Time: true}
+}
+
+type dateTruncFunc struct {
+	Unit qutil.IntervalUnit
+	V    interface{}
+}
+
+func (f *dateTruncFunc) String() string               { return expressionToString(f) }
+func (f *dateTruncFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *dateTruncFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.DateTrunc(ctx, buf, f.Unit, f.V)
+}
+
+// DateTrunc creates Function which truncates v to the unit, such as "DATE_TRUNC('DAY', v)".
+// It is useful to bucket the rows by day or week in GROUP BY clause.
+// The weeks start on Monday.
+// In MySQL it is written by DATE_FORMAT, and in SQLite it is written by DATETIME or STRFTIME.
+func DateTrunc(unit qutil.IntervalUnit, v interface{}) Function {
+	return &dateTruncFunc{Unit: unit, V: v}
+}
+
+type extractFunc struct {
+	Unit qutil.IntervalUnit
+	V    interface{}
+}
+
+func (f *extractFunc) String() string               { return expressionToString(f) }
+func (f *extractFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *extractFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.Extract(ctx, buf, f.Unit, f.V)
+}
+
+// Extract creates Function which returns the part of v, such as "EXTRACT(YEAR FROM v)".
+// In SQLite, it is written as "CAST(STRFTIME('%Y', v) AS INTEGER)".
+// Week is the ISO 8601 week number except in SQLite,
+// which returns the week of the year where the first Monday starts the week 1.
+func Extract(part qutil.IntervalUnit, v interface{}) Function {
+	return &extractFunc{Unit: part, V: v}
+}
+
+type dateDiffFunc struct {
+	Unit     qutil.IntervalUnit
+	From, To interface{}
+}
+
+func (f *dateDiffFunc) String() string               { return expressionToString(f) }
+func (f *dateDiffFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *dateDiffFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.DateDiff(ctx, buf, f.Unit, f.From, f.To)
+}
+
+// DateDiff creates Function which returns the number of the whole units from from to to,
+// such as "TIMESTAMPDIFF(DAY, from, to)" in MySQL.
+// The result is negative if to is before from, and it is truncated toward zero.
+// Year and Month count the calendar months,
+// so DateDiff(Month, "2020-01-31", "2020-02-29") is 0.
+//
+// In PostgreSQL it is written by AGE and EXTRACT(EPOCH FROM ...), so from and to must be timestamps.
+// In SQLite it is written by STRFTIME.
+func DateDiff(unit qutil.IntervalUnit, from, to interface{}) Function {
+	return &dateDiffFunc{Unit: unit, From: from, To: to}
+}
+
+type isoFunc struct {
+	Parse bool
+	V     interface{}
+}
+
+func (f *isoFunc) String() string               { return expressionToString(f) }
+func (f *isoFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *isoFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if f.Parse {
+		return ctx.Dialect.ParseISO(ctx, buf, f.V)
+	}
+	return ctx.Dialect.FormatISO(ctx, buf, f.V)
+}
+
+// FormatISO creates Function which formats the timestamp v into the ISO 8601 text such as "2006-01-02T15:04:05".
+// The fractional seconds and the time zone are not written.
+func FormatISO(v interface{}) Function {
+	return &isoFunc{V: v}
+}
+
+// ParseISO creates Function which parses the ISO 8601 text such as "2006-01-02T15:04:05" into the timestamp.
+func ParseISO(v interface{}) Function {
+	return &isoFunc{Parse: true, V: v}
+}
@@ -0,0 +1,201 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestDateTimeFunc(t *testing.T) {
+	x, y := C("x"), C("y")
+	for i, test := range []struct {
+		F                               Function
+		MySQL, PostgreSQL, SQLite, Fake string
+	}{
+		{
+			F:          SubInterval(x, Days(1), Hours(2)),
+			MySQL:      "`x` + INTERVAL -1 DAY + INTERVAL -2 HOUR",
+			PostgreSQL: `"x" + INTERVAL '-1 days' + INTERVAL '-2 hours'`,
+			SQLite:     `DATETIME("x", '-1 days', '-2 hours')`,
+			Fake:       `"x" + INTERVAL -1 DAY + INTERVAL -2 HOUR`,
+		},
+		{
+			F:          CurrentDate(),
+			MySQL:      "CURRENT_DATE",
+			PostgreSQL: `CURRENT_DATE`,
+			SQLite:     `CURRENT_DATE`,
+			Fake:       `CURRENT_DATE`,
+		},
+		{
+			F:          CurrentTime(),
+			MySQL:      "CURRENT_TIME",
+			PostgreSQL: `LOCALTIME`,
+			SQLite:     `CURRENT_TIME`,
+			Fake:       `CURRENT_TIME`,
+		},
+		{
+			F:          DateTrunc(Year, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-01-01 00:00:00') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('YEAR', "x")`,
+			SQLite:     `DATETIME("x", 'start of year')`,
+			Fake:       `DATE_TRUNC('YEAR', "x")`,
+		},
+		{
+			F:          DateTrunc(Month, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-%m-01 00:00:00') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('MONTH', "x")`,
+			SQLite:     `DATETIME("x", 'start of month')`,
+			Fake:       `DATE_TRUNC('MONTH', "x")`,
+		},
+		{
+			F:          DateTrunc(Week, x),
+			MySQL:      "CAST(DATE(`x`) - INTERVAL WEEKDAY(`x`) DAY AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('WEEK', "x")`,
+			SQLite:     `DATETIME("x", 'start of day', '-6 days', 'weekday 1')`,
+			Fake:       `DATE_TRUNC('WEEK', "x")`,
+		},
+		{
+			F:          DateTrunc(Day, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-%m-%d 00:00:00') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('DAY', "x")`,
+			SQLite:     `DATETIME("x", 'start of day')`,
+			Fake:       `DATE_TRUNC('DAY', "x")`,
+		},
+		{
+			F:          DateTrunc(Hour, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-%m-%d %H:00:00') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('HOUR', "x")`,
+			SQLite:     `STRFTIME('%Y-%m-%d %H:00:00', "x")`,
+			Fake:       `DATE_TRUNC('HOUR', "x")`,
+		},
+		{
+			F:          DateTrunc(Minute, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-%m-%d %H:%i:00') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('MINUTE', "x")`,
+			SQLite:     `STRFTIME('%Y-%m-%d %H:%M:00', "x")`,
+			Fake:       `DATE_TRUNC('MINUTE', "x")`,
+		},
+		{
+			F:          DateTrunc(Second, x),
+			MySQL:      "CAST(DATE_FORMAT(`x`, '%Y-%m-%d %H:%i:%s') AS DATETIME)",
+			PostgreSQL: `DATE_TRUNC('SECOND', "x")`,
+			SQLite:     `DATETIME("x")`,
+			Fake:       `DATE_TRUNC('SECOND', "x")`,
+		},
+		{
+			F:          Extract(Year, x),
+			MySQL:      "EXTRACT(YEAR FROM `x`)",
+			PostgreSQL: `EXTRACT(YEAR FROM "x")`,
+			SQLite:     `CAST(STRFTIME('%Y', "x") AS INTEGER)`,
+			Fake:       `EXTRACT(YEAR FROM "x")`,
+		},
+		{
+			F:          Extract(Week, x),
+			MySQL:      "WEEK(`x`, 3)",
+			PostgreSQL: `EXTRACT(WEEK FROM "x")`,
+			SQLite:     `CAST(STRFTIME('%W', "x") AS INTEGER)`,
+			Fake:       `EXTRACT(WEEK FROM "x")`,
+		},
+		{
+			F:          Extract(Second, x),
+			MySQL:      "EXTRACT(SECOND FROM `x`)",
+			PostgreSQL: `EXTRACT(SECOND FROM "x")`,
+			SQLite:     `CAST(STRFTIME('%S', "x") AS INTEGER)`,
+			Fake:       `EXTRACT(SECOND FROM "x")`,
+		},
+		{
+			F:          DateDiff(Day, x, y),
+			MySQL:      "TIMESTAMPDIFF(DAY, `x`, `y`)",
+			PostgreSQL: `CAST(TRUNC(EXTRACT(EPOCH FROM "y" - "x") / 86400) AS BIGINT)`,
+			SQLite:     `((STRFTIME('%s', "y") - STRFTIME('%s', "x")) / 86400)`,
+			Fake:       `TIMESTAMPDIFF(DAY, "x", "y")`,
+		},
+		{
+			F:          DateDiff(Second, x, y),
+			MySQL:      "TIMESTAMPDIFF(SECOND, `x`, `y`)",
+			PostgreSQL: `CAST(TRUNC(EXTRACT(EPOCH FROM "y" - "x")) AS BIGINT)`,
+			SQLite:     `((STRFTIME('%s', "y") - STRFTIME('%s', "x")) / 1)`,
+			Fake:       `TIMESTAMPDIFF(SECOND, "x", "y")`,
+		},
+		{
+			F:          DateDiff(Month, x, y),
+			MySQL:      "TIMESTAMPDIFF(MONTH, `x`, `y`)",
+			PostgreSQL: `CAST(EXTRACT(YEAR FROM AGE("y", "x")) * 12 + EXTRACT(MONTH FROM AGE("y", "x")) AS BIGINT)`,
+			SQLite: `(((STRFTIME('%Y', "y") - STRFTIME('%Y', "x")) * 12 + STRFTIME('%m', "y") - STRFTIME('%m', "x")` +
+				` - (STRFTIME('%Y-%m-%d %H:%M:%S', "y") >= STRFTIME('%Y-%m-%d %H:%M:%S', "x") AND STRFTIME('%d %H:%M:%S', "y") < STRFTIME('%d %H:%M:%S', "x"))` +
+				` + (STRFTIME('%Y-%m-%d %H:%M:%S', "y") < STRFTIME('%Y-%m-%d %H:%M:%S', "x") AND STRFTIME('%d %H:%M:%S', "y") > STRFTIME('%d %H:%M:%S', "x"))))`,
+			Fake: `TIMESTAMPDIFF(MONTH, "x", "y")`,
+		},
+		{
+			F:          DateDiff(Year, x, y),
+			MySQL:      "TIMESTAMPDIFF(YEAR, `x`, `y`)",
+			PostgreSQL: `CAST(EXTRACT(YEAR FROM AGE("y", "x")) AS BIGINT)`,
+			SQLite: `(((STRFTIME('%Y', "y") - STRFTIME('%Y', "x")) * 12 + STRFTIME('%m', "y") - STRFTIME('%m', "x")` +
+				` - (STRFTIME('%Y-%m-%d %H:%M:%S', "y") >= STRFTIME('%Y-%m-%d %H:%M:%S', "x") AND STRFTIME('%d %H:%M:%S', "y") < STRFTIME('%d %H:%M:%S', "x"))` +
+				` + (STRFTIME('%Y-%m-%d %H:%M:%S', "y") < STRFTIME('%Y-%m-%d %H:%M:%S', "x") AND STRFTIME('%d %H:%M:%S', "y") > STRFTIME('%d %H:%M:%S', "x"))) / 12)`,
+			Fake: `TIMESTAMPDIFF(YEAR, "x", "y")`,
+		},
+		{
+			F:          FormatISO(x),
+			MySQL:      "DATE_FORMAT(`x`, '%Y-%m-%dT%H:%i:%s')",
+			PostgreSQL: `TO_CHAR("x", 'YYYY-MM-DD"T"HH24:MI:SS')`,
+			SQLite:     `STRFTIME('%Y-%m-%dT%H:%M:%S', "x")`,
+			Fake:       `TO_CHAR("x", 'YYYY-MM-DD"T"HH24:MI:SS')`,
+		},
+		{
+			F:          ParseISO(x),
+			MySQL:      "STR_TO_DATE(`x`, '%Y-%m-%dT%H:%i:%s')",
+			PostgreSQL: `CAST("x" AS TIMESTAMP)`,
+			SQLite:     `DATETIME("x")`,
+			Fake:       `CAST("x" AS TIMESTAMP)`,
+		},
+	} {
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{
+			{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}, {SQLite, test.SQLite}, {nil, test.Fake},
+		} {
+			sql, _ := Select().Column(test.F.C()).SetDialect(d.D).ToSQL()
+			if want := "SELECT " + d.Want; sql != want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, want, sql)
+			}
+		}
+		if got, want := fmt.Sprint(clone(test.F)), fmt.Sprint(test.F); got != want {
+			t.Errorf("tests[%d] clone want %s got %s", i, want, got)
+		}
+	}
+}
+
+func TestDateTimeFuncArgs(t *testing.T) {
+	sql, args := Select().Column(DateTrunc(Week, "2020-01-01").C("w")).
+		Where(Gte(C("at"), ParseISO("2020-01-02T03:04:05"))).SetDialect(MySQL).ToSQL()
+	if want := "SELECT CAST(DATE(?) - INTERVAL WEEKDAY(?) DAY AS DATETIME) AS `w` WHERE `at` >= STR_TO_DATE(?, '%Y-%m-%dT%H:%i:%s')"; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if want, got := "[2020-01-01 2020-01-01 2020-01-02T03:04:05]", fmt.Sprint(args); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestDateTimeFuncValidate(t *testing.T) {
+	for i, f := range []Function{
+		DateTrunc(Millisecond, C("x")),
+		Extract(Microsecond, C("x")),
+		DateDiff(Millisecond, C("x"), C("y")),
+	} {
+		for _, d := range []qutil.Dialect{MySQL, PostgreSQL, SQLite, nil} {
+			b := Select().Column(f.C()).SetDialect(d)
+			if err := Validate(b); err == nil {
+				t.Errorf("tests[%d] %v want error got nil", i, d)
+			}
+			if sql, _ := b.ToSQL(); sql != "SELECT NULL" {
+				t.Errorf("tests[%d] %v want SELECT NULL got %s", i, d, sql)
+			}
+		}
+	}
+	if err := Validate(Select().Column(DateTrunc(Week, C("x")).C()).SetDialect(SQLite)); err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
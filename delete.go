@@ -27,6 +27,11 @@ func (b *ZDeleteBuilder) SetDialect(d qutil.Dialect) *ZDeleteBuilder {
 	return b
 }
 
+// Clone returns a deep copy of the builder.
+func (b *ZDeleteBuilder) Clone() *ZDeleteBuilder {
+	return clone(b).(*ZDeleteBuilder)
+}
+
 // From sets a table to the FROM clause.
 func (b *ZDeleteBuilder) From(table Table) *ZDeleteBuilder {
 	b.Table = table
@@ -47,7 +52,8 @@ func (b *ZDeleteBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 	buf = append(buf, "DELETE FROM "...)
 	buf = b.Table.WriteTable(ctx, buf)
 	if len(b.Wheres) > 0 {
-		buf = append(buf, " WHERE "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "WHERE "...)
 		buf = b.Wheres.WriteExpression(ctx, buf)
 	}
 	return buf
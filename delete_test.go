@@ -123,6 +123,14 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestDeleteClone(t *testing.T) {
+	for i, test := range deleteTests {
+		if r := fmt.Sprint(test.B.Clone()); r != test.V {
+			t.Errorf("tests[%d] %s want %v got %v", i, test.Name, test.Want, r)
+		}
+	}
+}
+
 func TestDeleteOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
@@ -0,0 +1,114 @@
+package q
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/oov/q/qutil"
+)
+
+// Distinct creates Expression such as "DISTINCT v".
+// It can be used in the aggregate functions such as Count(Distinct(v)).
+func Distinct(v interface{}) Expression {
+	return &distinctExpr{V: v}
+}
+
+type distinctExpr struct {
+	V interface{}
+}
+
+func (e *distinctExpr) String() string               { return expressionToString(e) }
+func (e *distinctExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *distinctExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, "DISTINCT "...)
+	return writeIntf(e.V, ctx, buf)
+}
+
+// rowNumberExpr represents "ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...)".
+type rowNumberExpr struct {
+	Partitions []Expression
+	Orders     []Ordering
+}
+
+func (e *rowNumberExpr) String() string               { return expressionToString(e) }
+func (e *rowNumberExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *rowNumberExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, "ROW_NUMBER() OVER (PARTITION BY "...)
+	for i, p := range e.Partitions {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = p.WriteExpression(ctx, buf)
+	}
+	if len(e.Orders) > 0 {
+		buf = append(buf, " ORDER BY "...)
+		buf = writeOrders(ctx, buf, e.Orders)
+	}
+	return append(buf, ')')
+}
+
+// outputName returns the name of c in the result set, or returns "" if c has no name.
+func outputName(c Column) string {
+	switch v := c.(type) {
+	case *columnAlias:
+		return v.Alias
+	case column:
+		return string(v)
+	case *columnWithTable:
+		return string(v.column)
+	}
+	return ""
+}
+
+// emulateDistinctOn returns the builder which emulates DISTINCT ON by ROW_NUMBER window function.
+// b must have the columns.
+func (b *ZSelectBuilder) emulateDistinctOn() *ZSelectBuilder {
+	inner := *b
+	inner.DistinctOns, inner.Orders, inner.LimitCount, inner.StartOffset = nil, nil, nil, nil
+	inner.IsDistinct = false
+	inner.Columns = make([]Column, 0, len(b.Columns)+len(b.Orders)+1)
+
+	outer := Select(b.Beginning)
+	outer.IsDistinct = b.IsDistinct
+	outer.LimitCount, outer.StartOffset = b.LimitCount, b.StartOffset
+
+	// The subquery can't have the same column names such as "u"."id" and "p"."id",
+	// so they are renamed in the subquery and are given back the original names in the outer query.
+	counts := map[string]int{}
+	for _, c := range b.Columns {
+		counts[strings.ToLower(outputName(c))]++
+	}
+	keys := map[string]string{}
+	for i, c := range b.Columns {
+		name := outputName(c)
+		switch {
+		case name == "":
+			name = "q_c" + strconv.Itoa(i)
+			c = c.C(name)
+			outer.Column(C(name))
+		case counts[strings.ToLower(name)] > 1:
+			unique := "q_c" + strconv.Itoa(i)
+			c = c.C(unique)
+			outer.Column(C(unique).C(name))
+			name = unique
+		default:
+			outer.Column(C(name))
+		}
+		inner.Columns = append(inner.Columns, c)
+		keys[expressionKey(c)] = name
+		if a, ok := c.(*columnAlias); ok {
+			keys[expressionKey(a.Column)] = name
+		}
+	}
+	for i, o := range b.Orders {
+		name, ok := keys[expressionKey(o.Expression)]
+		if !ok {
+			name = "q_o" + strconv.Itoa(i)
+			inner.Columns = append(inner.Columns, o.Expression.C(name))
+		}
+		o.Expression = C(name)
+		outer.Order(o)
+	}
+	inner.Columns = append(inner.Columns, (&rowNumberExpr{Partitions: b.DistinctOns, Orders: b.Orders}).C("q_rn"))
+	return outer.From(inner.T("q_t")).Where(Unsafe(C("q_rn"), " = 1"))
+}
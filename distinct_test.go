@@ -0,0 +1,88 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestDistinct(t *testing.T) {
+	user, post := T("user", "u"), T("post", "p")
+	for i, test := range []struct {
+		B    *ZSelectBuilder
+		D    qutil.Dialect
+		SQL  string
+		Args string
+	}{
+		{
+			B:    Select().Distinct().Column(user.C("name")).From(user),
+			D:    MySQL,
+			SQL:  "SELECT DISTINCT `u`.`name` FROM `user` AS `u`",
+			Args: "[]",
+		},
+		{
+			B:    Select().Column(Count(Distinct(user.C("name"))).C("c")).From(user),
+			D:    SQLite,
+			SQL:  `SELECT COUNT(DISTINCT "u"."name") AS "c" FROM "user" AS "u"`,
+			Args: "[]",
+		},
+		{
+			B: Select().DistinctOn(post.C("user_id")).Column(post.C("user_id"), post.C("title")).From(post).
+				Where(Eq(post.C("draft"), false)).OrderBy(post.C("user_id"), true).OrderBy(post.C("created_at"), false).Limit(10),
+			D:    PostgreSQL,
+			SQL:  `SELECT DISTINCT ON ("p"."user_id") "p"."user_id", "p"."title" FROM "post" AS "p" WHERE "p"."draft" = $1 ORDER BY "p"."user_id" ASC, "p"."created_at" DESC LIMIT $2`,
+			Args: "[false 10]",
+		},
+		{
+			B: Select().DistinctOn(post.C("user_id")).Column(post.C("user_id"), post.C("title", "t"), Add(post.C("score"), 1).C()).From(post).
+				Where(Eq(post.C("draft"), false)).OrderBy(post.C("user_id"), true).OrderBy(post.C("created_at"), false).Limit(10),
+			D:    MySQL,
+			SQL:  "SELECT `user_id`, `t`, `q_c2` FROM (SELECT `p`.`user_id`, `p`.`title` AS `t`, `p`.`score` + ? AS `q_c2`, `p`.`created_at` AS `q_o1`, ROW_NUMBER() OVER (PARTITION BY `p`.`user_id` ORDER BY `p`.`user_id` ASC, `p`.`created_at` DESC) AS `q_rn` FROM `post` AS `p` WHERE `p`.`draft` = ?) AS `q_t` WHERE `q_rn` = 1 ORDER BY `user_id` ASC, `q_o1` DESC LIMIT ?",
+			Args: "[1 false 10]",
+		},
+		{
+			B:    Select().DistinctOn(post.C("user_id")).Column(post.C("title")).From(post).OrderBy(post.C("title"), true).SetDialect(SQLite),
+			D:    SQLite,
+			SQL:  `SELECT "title" FROM (SELECT "p"."title", ROW_NUMBER() OVER (PARTITION BY "p"."user_id" ORDER BY "p"."title" ASC) AS "q_rn" FROM "post" AS "p") AS "q_t" WHERE "q_rn" = 1 ORDER BY "title" ASC`,
+			Args: "[]",
+		},
+		{
+			B: Select().DistinctOn(user.C("id")).Column(user.C("id"), post.C("id"), post.C("title", "ID")).
+				From(user.InnerJoin(post, Eq(post.C("user_id"), user.C("id")))).OrderBy(user.C("id"), true).OrderBy(post.C("id"), false),
+			D:    SQLite,
+			SQL:  `SELECT "q_c0" AS "id", "q_c1" AS "id", "q_c2" AS "ID" FROM (SELECT "u"."id" AS "q_c0", "p"."id" AS "q_c1", "p"."title" AS "q_c2", ROW_NUMBER() OVER (PARTITION BY "u"."id" ORDER BY "u"."id" ASC, "p"."id" DESC) AS "q_rn" FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id") AS "q_t" WHERE "q_rn" = 1 ORDER BY "q_c0" ASC, "q_c1" DESC`,
+			Args: "[]",
+		},
+	} {
+		sql, args := test.B.SetDialect(test.D).ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] want %s got %s", i, test.Args, got)
+		}
+	}
+}
+
+func TestDistinctOnValidate(t *testing.T) {
+	b := Select().DistinctOn(C("a")).From(T("t"))
+	if err := Validate(b.SetDialect(MySQL)); err == nil {
+		t.Errorf("want error got nil")
+	}
+	if err := Validate(b.SetDialect(PostgreSQL)); err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
+
+func TestDistinctClone(t *testing.T) {
+	b := Select().DistinctOn(C("a")).Column(C("a")).From(T("t"))
+	c := b.Clone()
+	c.DistinctOns[0] = C("b")
+	if want, got := `SELECT DISTINCT ON ("a") "a" FROM "t" []`, b.String(); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+	if want, got := `SELECT COUNT(*) FROM (SELECT DISTINCT ON ("a") "a" FROM "t") AS "t" []`, b.CountQuery().String(); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
@@ -136,16 +136,20 @@ func ExampleTable() {
 	user := q.T("user", "usr")
 	post := q.T("post", "pst")
 	// user.id -> post.user_id
-	user.InnerJoin(post, q.Eq(user.C("id"), post.C("user_id")))
-	fmt.Println("Short:", user)
+	fmt.Println("Short:", user.InnerJoin(post, q.Eq(user.C("id"), post.C("user_id"))))
 
 	postTag := q.T("posttag", "rel")
 	tag := q.T("tag", "tg")
-	// post.id -> posttag.post_id
-	post.InnerJoin(postTag, q.Eq(post.C("id"), postTag.C("post_id")))
-	// posttag.tag_id -> tag.id
-	postTag.InnerJoin(tag, q.Eq(postTag.C("tag_id"), tag.C("id")))
-	fmt.Println("Long: ", user)
+	// InnerJoin returns a new Table, so nested joins are built from the innermost one.
+	fmt.Println("Long: ", user.InnerJoin(
+		// post.id -> posttag.post_id
+		post.InnerJoin(
+			// posttag.tag_id -> tag.id
+			postTag.InnerJoin(tag, q.Eq(postTag.C("tag_id"), tag.C("id"))),
+			q.Eq(post.C("id"), postTag.C("post_id")),
+		),
+		q.Eq(user.C("id"), post.C("user_id")),
+	))
 	// Output:
 	// Short: "user" AS "usr" INNER JOIN "post" AS "pst" ON "usr"."id" = "pst"."user_id" []
 	// Long:  "user" AS "usr" INNER JOIN ("post" AS "pst" INNER JOIN ("posttag" AS "rel" INNER JOIN "tag" AS "tg" ON "rel"."tag_id" = "tg"."id") ON "pst"."id" = "rel"."post_id") ON "usr"."id" = "pst"."user_id" []
@@ -458,3 +462,121 @@ func ExampleZInsertBuilder_Returning() {
 	// PostgreSQL INSERT INTO "user"("name") VALUES ($1) RETURNING "id", "name" AS "n" [hackme]
 	// MySQL INSERT INTO `user`(`name`) VALUES (?) [hackme]
 }
+
+func ExampleFingerprint() {
+	user := q.T("user")
+	a := q.Select().From(user).Where(q.Eq(user.C("id"), []int{1, 2, 3}))
+	b := q.Select().From(user).Where(q.Eq(user.C("id"), []int{4}))
+	as, ah := q.Fingerprint(a)
+	bs, bh := q.Fingerprint(b)
+	fmt.Println(as)
+	fmt.Println(as == bs, ah == bh)
+	// Output:
+	// SELECT * FROM "user" WHERE "user"."id" IN (...)
+	// true true
+}
+
+func ExampleFormat() {
+	user, post := q.T("user", "u"), q.T("post", "p")
+	sel := q.Select().Column(user.C("name"), q.Count(post.C("id")).C("count")).From(
+		user.LeftJoin(post, q.Eq(post.C("user_id"), user.C("id"))),
+	).Where(
+		q.Eq(user.C("deleted"), false),
+		q.Gte(user.C("age"), 18),
+	).GroupBy(user.C("name"))
+	sql, args := q.Format(sel)
+	fmt.Println(sql)
+	fmt.Println(args)
+	// Output:
+	// SELECT "u"."name", COUNT("p"."id") AS "count"
+	// FROM "user" AS "u"
+	//   LEFT JOIN "post" AS "p" ON "p"."user_id" = "u"."id"
+	// WHERE ("u"."deleted" = ?)
+	//   AND ("u"."age" >= ?)
+	// GROUP BY "u"."name"
+	// [false 18]
+}
+
+func ExampleSimplify() {
+	user := q.T("user")
+	sel := q.Select().From(user).Where(
+		q.And(q.Eq(user.C("deleted"), false), q.Eq(user.C("deleted"), false)),
+		q.Or(q.Eq(user.C("id"), 1), q.Eq(user.C("name"), "a"), q.Or()),
+		q.Neq(user.C("id"), []int{}),
+	)
+	fmt.Println(sel)
+	fmt.Println(q.Simplify(sel))
+	// Output:
+	// SELECT * FROM "user" WHERE (("user"."deleted" = ?)AND("user"."deleted" = ?))AND(("user"."id" = ?)OR("user"."name" = ?)OR(('empty' = 'OR')))AND('IN' != '()') [false false 1 a]
+	// SELECT * FROM "user" WHERE "user"."deleted" = ? AND ("user"."id" = ? OR "user"."name" = ?) [false 1 a]
+}
+
+func ExampleZSelectBuilder_Seek() {
+	post := q.T("post")
+	sel := q.Select().From(post).OrderBy(post.C("created_at"), false).OrderBy(post.C("id"), true)
+
+	// The cursor token is usually passed from the client.
+	token, _ := q.EncodeCursor([]interface{}{"2020-01-01 00:00:00", 100})
+	cursor, err := q.DecodeCursor(token)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(sel.Seek(cursor, 20).SetDialect(q.PostgreSQL).ToSQL())
+	// Output:
+	// SELECT * FROM "post" WHERE ("post"."created_at" < $1)OR(("post"."created_at" = $2)AND("post"."id" > $3)) ORDER BY "post"."created_at" DESC, "post"."id" ASC LIMIT $4 [2020-01-01 00:00:00 2020-01-01 00:00:00 100 21]
+}
+
+func ExampleParse() {
+	b, err := q.Parse("SELECT `id`, `name` FROM `user` WHERE `age` >= ?", 18)
+	if err != nil {
+		panic(err)
+	}
+	sel := b.(*q.ZSelectBuilder)
+	sel.Where(q.Eq(q.C("deleted"), false)).SetDialect(q.PostgreSQL)
+	fmt.Println(sel.ToSQL())
+	// Output:
+	// SELECT "id", "name" FROM "user" WHERE ("age" >= $1)AND("deleted" = $2) [18 false]
+}
+
+func ExampleParseFilter() {
+	user := q.T("user")
+	fields := q.Fields{
+		"name":       {Column: user.C("name")},
+		"age":        {Column: user.C("age"), Type: q.IntField},
+		"status":     {Column: user.C("status"), Ops: []string{"eq", "in"}},
+		"created_at": {Column: user.C("created_at"), Type: q.TimeField, NoFilter: true},
+	}
+
+	// The parameters are usually passed from the client such as "?sort=-created_at,name&filter=age>=18,status:in:a|b".
+	orders, err := q.ParseSort("-created_at,name", fields)
+	if err != nil {
+		panic(err)
+	}
+	conds, err := q.ParseFilter("age>=18,status:in:a|b", fields)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(q.Select().From(user).Where(conds...).Order(orders...).SetDialect(q.MySQL).ToSQL())
+
+	_, err = q.ParseFilter("age>=x", fields)
+	fmt.Println(err)
+	// Output:
+	// SELECT * FROM `user` WHERE (`user`.`age` >= ?)AND(`user`.`status` IN (?,?)) ORDER BY `user`.`created_at` DESC, `user`.`name` ASC [18 a b]
+	// q: invalid filter "age>=x": "x" is not an integer
+}
+
+func ExampleFilter() {
+	// The optional conditions of the search screen are represented by pointers and slices.
+	type userFilter struct {
+		Name   *string  `q:"name,contains"`
+		MinAge *int     `q:"age,gte"`
+		MaxAge *int     `q:"age,lt"`
+		Status []string `q:"status"`
+	}
+	minAge := 18
+	user := q.T("user")
+	f := userFilter{MinAge: &minAge, Status: []string{"active", "pending"}}
+	fmt.Println(q.Select().From(user).Where(q.Filter(f, user)...).SetDialect(q.MySQL).ToSQL())
+	// Output:
+	// SELECT * FROM `user` WHERE (`user`.`age` >= ?)AND(`user`.`status` IN (?,?)) [18 active pending]
+}
@@ -0,0 +1,118 @@
+package q
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DB represents a database handle such as *sql.DB, *sql.Tx and *sql.Conn.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// HookEvent represents a statement which is executed by ZExecutor.
+type HookEvent struct {
+	Kind string // "SELECT", "INSERT", "UPDATE" or "DELETE".
+	SQL  string
+	Args []interface{}
+
+	// The following fields are available in Hook.After only.
+	Duration     time.Duration
+	RowsAffected int64 // -1 when unknown, such as in the case of a query.
+	Err          error
+}
+
+// Hook represents callbacks around each statement execution.
+type Hook interface {
+	// Before is called before executing the statement.
+	// The returned context is passed to the next hook and is passed to After of the same hook.
+	// The context which is returned by the last hook is used to execute the statement.
+	Before(ctx context.Context, e *HookEvent) context.Context
+	// After is called after executing the statement in the reverse order of Before.
+	After(ctx context.Context, e *HookEvent)
+}
+
+// ZExecutor executes builders on DB and calls hooks around each statement.
+type ZExecutor struct {
+	DB    DB
+	Hooks []Hook
+}
+
+// Executor creates ZExecutor.
+func Executor(db DB, hooks ...Hook) *ZExecutor {
+	return &ZExecutor{DB: db, Hooks: hooks}
+}
+
+func builderKind(b Builder) string {
+	switch b.(type) {
+	case *ZSelectBuilder:
+		return "SELECT"
+	case *ZInsertBuilder:
+		return "INSERT"
+	case *ZUpdateBuilder:
+		return "UPDATE"
+	case *ZDeleteBuilder:
+		return "DELETE"
+	}
+	return ""
+}
+
+// before calls Before of the hooks, and returns the contexts which are returned by each hook.
+// The last one is the context to execute the statement, or ctx if there is no hook.
+func (e *ZExecutor) before(ctx context.Context, b Builder) ([]context.Context, *HookEvent, error) {
+	d, cud := builderOptions(b)
+	buf, qctx := write(b, d, 128, 8, cud)
+	if qctx.Err != nil {
+		return nil, nil, qctx.Err
+	}
+	ev := &HookEvent{Kind: builderKind(b), SQL: string(buf), Args: qctx.Args, RowsAffected: -1}
+	ctxs := make([]context.Context, len(e.Hooks), len(e.Hooks)+1)
+	for i, h := range e.Hooks {
+		ctx = h.Before(ctx, ev)
+		ctxs[i] = ctx
+	}
+	return append(ctxs, ctx), ev, nil
+}
+
+func (e *ZExecutor) after(ctxs []context.Context, ev *HookEvent) {
+	for i := len(e.Hooks) - 1; i >= 0; i-- {
+		e.Hooks[i].After(ctxs[i], ev)
+	}
+}
+
+// Exec executes b without returning any rows.
+// If b can't be written in the dialect, the error is returned without executing and calling hooks.
+func (e *ZExecutor) Exec(ctx context.Context, b Builder) (sql.Result, error) {
+	ctxs, ev, err := e.before(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	r, err := e.DB.ExecContext(ctxs[len(ctxs)-1], ev.SQL, ev.Args...)
+	ev.Duration = time.Since(start)
+	if err == nil {
+		if n, err := r.RowsAffected(); err == nil {
+			ev.RowsAffected = n
+		}
+	}
+	ev.Err = err
+	e.after(ctxs, ev)
+	return r, err
+}
+
+// Query executes b that returns rows.
+// If b can't be written in the dialect, the error is returned without executing and calling hooks.
+func (e *ZExecutor) Query(ctx context.Context, b Builder) (*sql.Rows, error) {
+	ctxs, ev, err := e.before(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := e.DB.QueryContext(ctxs[len(ctxs)-1], ev.SQL, ev.Args...)
+	ev.Duration = time.Since(start)
+	ev.Err = err
+	e.after(ctxs, ev)
+	return rows, err
+}
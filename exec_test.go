@@ -0,0 +1,187 @@
+package q
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver which records statements without any database.
+type fakeDriver struct {
+	Queries []string
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "error") {
+		return nil, errors.New("fake error")
+	}
+	c.d.Queries = append(c.d.Queries, query)
+	return &fakeStmt{}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct{}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(3), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"id"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+var fakeDriverCount int
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	fakeDriverCount++
+	name := "qfake" + strconv.Itoa(fakeDriverCount)
+	d := &fakeDriver{}
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, d
+}
+
+type recordHook struct {
+	Name string
+	Log  *[]string
+	Evs  []HookEvent
+}
+
+type recordKey struct{}
+
+func (h *recordHook) Before(ctx context.Context, e *HookEvent) context.Context {
+	*h.Log = append(*h.Log, "before "+h.Name)
+	return context.WithValue(ctx, recordKey{}, h.Name)
+}
+
+func (h *recordHook) After(ctx context.Context, e *HookEvent) {
+	*h.Log = append(*h.Log, "after "+h.Name+" "+ctx.Value(recordKey{}).(string))
+	h.Evs = append(h.Evs, *e)
+}
+
+func TestExecutor(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	var log []string
+	h1, h2 := &recordHook{Name: "1", Log: &log}, &recordHook{Name: "2", Log: &log}
+	e := Executor(db, h1, h2)
+
+	r, err := e.Exec(context.Background(), Update(T("user")).Set(C("age"), 18).Where(Eq(C("id"), 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := r.RowsAffected(); n != 3 {
+		t.Errorf("want 3 got %d", n)
+	}
+	rows, err := e.Query(context.Background(), Select().From(T("user")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if _, err = e.Exec(context.Background(), Delete(T("error"))); err == nil {
+		t.Errorf("want error got nil")
+	}
+
+	if want, got := `before 1,before 2,after 2 2,after 1 1,before 1,before 2,after 2 2,after 1 1,before 1,before 2,after 2 2,after 1 1`, strings.Join(log, ","); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+	if want, got := `UPDATE "user" SET "age" = ? WHERE "id" = ?,SELECT * FROM "user"`, strings.Join(d.Queries, ","); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	evs := h1.Evs
+	if len(evs) != 3 {
+		t.Fatalf("want 3 events got %d", len(evs))
+	}
+	if ev := evs[0]; ev.Kind != "UPDATE" || ev.SQL != d.Queries[0] || len(ev.Args) != 2 || ev.RowsAffected != 3 || ev.Err != nil || ev.Duration <= 0 {
+		t.Errorf("unexpected event %#v", ev)
+	}
+	if ev := evs[1]; ev.Kind != "SELECT" || ev.RowsAffected != -1 || ev.Err != nil {
+		t.Errorf("unexpected event %#v", ev)
+	}
+	if ev := evs[2]; ev.Kind != "DELETE" || ev.RowsAffected != -1 || ev.Err == nil {
+		t.Errorf("unexpected event %#v", ev)
+	}
+}
+
+type fakeTracer struct {
+	Spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	s := &fakeSpan{Name: spanName, Attrs: map[string]interface{}{}}
+	t.Spans = append(t.Spans, s)
+	return ctx, s
+}
+
+type fakeSpan struct {
+	Name  string
+	Attrs map[string]interface{}
+	Err   error
+	Ends  int
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.Attrs[key] = value }
+func (s *fakeSpan) RecordError(err error)                      { s.Err = err }
+func (s *fakeSpan) End()                                       { s.Ends++ }
+
+func TestTracerHook(t *testing.T) {
+	db, _ := openFakeDB(t)
+	defer db.Close()
+
+	tr := &fakeTracer{}
+	e := Executor(db, TracerHook(tr))
+	e.Exec(context.Background(), Update(T("user")).Set(C("age"), 1))
+	e.Exec(context.Background(), Delete(T("error")))
+
+	if len(tr.Spans) != 2 {
+		t.Fatalf("want 2 spans got %d", len(tr.Spans))
+	}
+	s := tr.Spans[0]
+	if s.Name != "q.UPDATE" || s.Attrs["db.statement"] != `UPDATE "user" SET "age" = ?` || s.Attrs["db.rows_affected"] != int64(3) || s.Err != nil || s.Ends != 1 {
+		t.Errorf("unexpected span %#v", s)
+	}
+	s = tr.Spans[1]
+	if s.Name != "q.DELETE" || s.Err == nil || s.Ends != 1 {
+		t.Errorf("unexpected span %#v", s)
+	}
+}
+
+func TestNestedTracerHooks(t *testing.T) {
+	db, _ := openFakeDB(t)
+	defer db.Close()
+
+	tr := &fakeTracer{}
+	e := Executor(db, TracerHook(tr), TracerHook(tr))
+	e.Exec(context.Background(), Update(T("user")).Set(C("age"), 1))
+
+	if len(tr.Spans) != 2 {
+		t.Fatalf("want 2 spans got %d", len(tr.Spans))
+	}
+	for i, s := range tr.Spans {
+		if s.Ends != 1 || s.Attrs["db.rows_affected"] != int64(3) {
+			t.Errorf("spans[%d] unexpected span %#v", i, s)
+		}
+	}
+}
@@ -71,7 +71,14 @@ func valueToInV(v reflect.Value) inVariable {
 // Eq creates Expression such as "l = r".
 // But when you pass nil to one of a pair, Eq creates "x IS NULL" instead.
 // In the same way, when you pass slice of any type to r, Eq creates "x IN (?)".
+// If r is Quantifier such as Any(Array(slice)) or created by Array, Eq creates "x = ANY (?)".
 func Eq(l, r interface{}) Expression {
+	if e, ok := quantified(l, "=", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, "=", r); ok {
+		return e
+	}
 	if rv := reflect.ValueOf(r); rv.Kind() == reflect.Slice {
 		if _, ok := r.(Expression); !ok {
 			return &inExpr{Left: l, Right: valueToInV(rv)}
@@ -83,7 +90,14 @@ func Eq(l, r interface{}) Expression {
 // Neq creates Expression such as "l != r".
 // But when you pass nil to one of a pair, Neq creates "x IS NOT NULL" instead.
 // In the same way, when you pass slice of any type to r, Neq creates "x NOT IN (?)".
+// If r is Quantifier such as All(Array(slice)) or created by Array, Neq creates "x != ALL (?)".
 func Neq(l, r interface{}) Expression {
+	if e, ok := quantified(l, "!=", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, "!=", r); ok {
+		return e
+	}
 	if rv := reflect.ValueOf(r); rv.Kind() == reflect.Slice {
 		if _, ok := r.(Expression); !ok {
 			return &notInExpr{Left: l, Right: valueToInV(rv)}
@@ -94,6 +108,9 @@ func Neq(l, r interface{}) Expression {
 
 // In creates Expression such as "l IN r".
 func In(l, r interface{}) Expression {
+	if e, ok := rowValue(l, "IN", r); ok {
+		return e
+	}
 	if rv := reflect.ValueOf(r); rv.Kind() == reflect.Slice {
 		if _, ok := r.(Expression); !ok {
 			return &inExpr{Left: l, Right: valueToInV(rv)}
@@ -104,6 +121,9 @@ func In(l, r interface{}) Expression {
 
 // NotIn creates Expression such as "l NOT IN r".
 func NotIn(l, r interface{}) Expression {
+	if e, ok := rowValue(l, "NOT IN", r); ok {
+		return e
+	}
 	if rv := reflect.ValueOf(r); rv.Kind() == reflect.Slice {
 		if _, ok := r.(Expression); !ok {
 			return &notInExpr{Left: l, Right: valueToInV(rv)}
@@ -113,16 +133,48 @@ func NotIn(l, r interface{}) Expression {
 }
 
 // Gt creates Expression such as "l > r".
-func Gt(l, r interface{}) Expression { return &gtExpr{Left: l, Right: r} }
+func Gt(l, r interface{}) Expression {
+	if e, ok := quantified(l, ">", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, ">", r); ok {
+		return e
+	}
+	return &gtExpr{Left: l, Right: r}
+}
 
 // Gte creates Expression such as "l >= r".
-func Gte(l, r interface{}) Expression { return &gteExpr{Left: l, Right: r} }
+func Gte(l, r interface{}) Expression {
+	if e, ok := quantified(l, ">=", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, ">=", r); ok {
+		return e
+	}
+	return &gteExpr{Left: l, Right: r}
+}
 
 // Lt creates Expression such as "l < r".
-func Lt(l, r interface{}) Expression { return &ltExpr{Left: l, Right: r} }
+func Lt(l, r interface{}) Expression {
+	if e, ok := quantified(l, "<", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, "<", r); ok {
+		return e
+	}
+	return &ltExpr{Left: l, Right: r}
+}
 
 // Lte creates Expression such as "l <= r".
-func Lte(l, r interface{}) Expression { return &lteExpr{Left: l, Right: r} }
+func Lte(l, r interface{}) Expression {
+	if e, ok := quantified(l, "<=", r); ok {
+		return e
+	}
+	if e, ok := rowValue(l, "<=", r); ok {
+		return e
+	}
+	return &lteExpr{Left: l, Right: r}
+}
 
 // Expressions represents combination of an expression.
 type Expressions Expression
@@ -233,6 +285,10 @@ func (v inVariable) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
 	if ln == 0 {
 		return append(buf, "()"...)
 	}
+	if ctx.Normalize {
+		ctx.Args = append(ctx.Args, v...)
+		return append(buf, "(...)"...)
+	}
 
 	buf = append(buf, '(')
 	buf = ctx.Placeholder.Next(buf)
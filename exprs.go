@@ -192,14 +192,16 @@ func (e ZAndExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
 	case 1:
 		return e[0].WriteExpression(ctx, buf)
 	}
+	ctx.Indent++
 	buf = append(buf, '(')
 	buf = e[0].WriteExpression(ctx, buf)
 	buf = append(buf, ')')
 	for _, cd := range e[1:] {
-		buf = append(buf, "AND("...)
+		buf = writeLogicalOperator(ctx, buf, "AND")
 		buf = cd.WriteExpression(ctx, buf)
 		buf = append(buf, ')')
 	}
+	ctx.Indent--
 	return buf
 }
 
@@ -221,13 +223,15 @@ func (e ZOrExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
 	case 1:
 		return e[0].WriteExpression(ctx, buf)
 	}
+	ctx.Indent++
 	buf = append(buf, '(')
 	buf = e[0].WriteExpression(ctx, buf)
 	buf = append(buf, ')')
 	for _, cd := range e[1:] {
-		buf = append(buf, "OR("...)
+		buf = writeLogicalOperator(ctx, buf, "OR")
 		buf = cd.WriteExpression(ctx, buf)
 		buf = append(buf, ')')
 	}
+	ctx.Indent--
 	return buf
 }
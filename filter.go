@@ -0,0 +1,176 @@
+package q
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Filter creates the conditions from the fields of struct v, which can be passed to ZSelectBuilder.Where.
+// v must be a struct or a pointer to a struct.
+//
+// The fields which have the tag such as `q:"name,op"` are used, and the others are ignored.
+// name is the column name, and it is qualified by table if table is given.
+// op is the operator such as "gte", and "eq" is used if it is omitted.
+// The operators are:
+//
+//	eq          "x = value", or "x IN (value)" if value is a slice
+//	ne          "x != value", or "x NOT IN (value)" if value is a slice
+//	gt          "x > value"
+//	gte         "x >= value"
+//	lt          "x < value"
+//	lte         "x <= value"
+//	in          "x IN (value)"
+//	nin         "x NOT IN (value)"
+//	like        "x LIKE value", value is used as the pattern as it is
+//	notlike     "x NOT LIKE value"
+//	ilike       "x ILIKE value", it depends on the dialect
+//	contains    "x LIKE '%value%'", the wildcard characters in value are escaped
+//	startswith  "x LIKE 'value%'"
+//	endswith    "x LIKE '%value'"
+//
+// The fields which are nil, such as nil pointers and nil slices, are skipped,
+// so the optional conditions can be represented by pointers.
+// The pointers are dereferenced, and the embedded structs without the tag are expanded.
+// Filter panics if the tag has an unknown operator.
+func Filter(v interface{}, table ...Table) ZAndExpr {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic("q: Filter needs a struct or a pointer to a struct.")
+	}
+	var t Table
+	if len(table) > 0 {
+		t = table[0]
+	}
+	return appendStructFilter(ZAndExpr{}, rv, t)
+}
+
+func appendStructFilter(r ZAndExpr, rv reflect.Value, t Table) ZAndExpr {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok := sf.Tag.Lookup("q")
+		if !ok {
+			if sf.Anonymous {
+				if fv, ok := filterValue(rv.Field(i)); ok && fv.Kind() == reflect.Struct {
+					r = appendStructFilter(r, fv, t)
+				}
+			}
+			continue
+		}
+		if tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+		fv, ok := filterValue(rv.Field(i))
+		if !ok {
+			continue
+		}
+		name, op := tag, ""
+		if j := strings.IndexByte(tag, ','); j != -1 {
+			name, op = tag[:j], tag[j+1:]
+		}
+		var c Column
+		if t != nil {
+			c = t.C(name)
+		} else {
+			c = C(name)
+		}
+		e := filterOp(c, op, fv.Interface())
+		if e == nil {
+			panic("q: unknown filter operator " + op + " in the field " + sf.Name + ".")
+		}
+		r = append(r, e)
+	}
+	return r
+}
+
+// filterValue dereferences the pointers in v,
+// and reports false if v is nil.
+func filterValue(v reflect.Value) (reflect.Value, bool) {
+	for {
+		switch v.Kind() {
+		case reflect.Invalid:
+			return v, false
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		case reflect.Slice, reflect.Map:
+			return v, !v.IsNil()
+		default:
+			return v, true
+		}
+	}
+}
+
+// MapFilter creates the conditions such as "column = value" from m,
+// which can be passed to ZSelectBuilder.Where.
+// The nil values are skipped, and the slices are converted into "column IN (value)" as Eq does.
+// The conditions are sorted by the columns, so the result is always the same SQL.
+func MapFilter(m map[Column]interface{}) ZAndExpr {
+	r := make(ZAndExpr, 0, len(m))
+	keys := make([]string, 0, len(m))
+	byKey := make(map[string]Expression, len(m))
+	for c, v := range m {
+		fv, ok := filterValue(reflect.ValueOf(v))
+		if !ok {
+			continue
+		}
+		k := expressionKey(c)
+		keys = append(keys, k)
+		byKey[k] = Eq(c, fv.Interface())
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r = append(r, byKey[k])
+	}
+	return r
+}
+
+// filterOp creates the condition which compares c with v by op,
+// or returns nil if op is unknown.
+func filterOp(c Column, op string, v interface{}) Expression {
+	switch op {
+	case "", "eq":
+		return Eq(c, v)
+	case "ne":
+		return Neq(c, v)
+	case "gt":
+		return Gt(c, v)
+	case "gte":
+		return Gte(c, v)
+	case "lt":
+		return Lt(c, v)
+	case "lte":
+		return Lte(c, v)
+	case "in":
+		return In(c, v)
+	case "nin":
+		return NotIn(c, v)
+	case "like":
+		return Like(c, v)
+	case "notlike":
+		return NotLike(c, v)
+	case "ilike":
+		return ILike(c, v)
+	case "contains":
+		return Contains(c, filterString(v))
+	case "startswith":
+		return StartsWith(c, filterString(v))
+	case "endswith":
+		return EndsWith(c, filterString(v))
+	}
+	return nil
+}
+
+func filterString(v interface{}) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,121 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+)
+
+type filterPaging struct {
+	Status []string `q:"status"`
+}
+
+type testFilter struct {
+	filterPaging
+	Name     *string `q:"name,like"`
+	MinAge   *int    `q:"age,gte"`
+	MaxAge   *int    `q:"age,lt"`
+	Keyword  string  `q:"title,contains"`
+	Excludes []int   `q:"id,ne"`
+	Deleted  bool    `q:"deleted"`
+	Ignored  *int    `q:"-"`
+	NoTag    *int
+	private  *int      `q:"private"`
+	Score    **float64 `q:"score,lte"`
+}
+
+func TestFilter(t *testing.T) {
+	name, age, score := "a%", 18, 1.5
+	pscore := &score
+	for i, test := range []struct {
+		V    interface{}
+		T    []Table
+		SQL  string
+		Args string
+	}{
+		{
+			V:    testFilter{},
+			SQL:  `SELECT * FROM "user" WHERE ("title" LIKE ? ESCAPE '!')AND("deleted" = ?)`,
+			Args: "[%% false]",
+		},
+		{
+			V: &testFilter{
+				filterPaging: filterPaging{Status: []string{"a", "b"}},
+				Name:         &name, MinAge: &age, Keyword: "x_y", Excludes: []int{1, 2}, Deleted: true, Score: &pscore,
+			},
+			SQL:  `SELECT * FROM "user" WHERE ("status" IN (?,?))AND("name" LIKE ?)AND("age" >= ?)AND("title" LIKE ? ESCAPE '!')AND("id" NOT IN (?,?))AND("deleted" = ?)AND("score" <= ?)`,
+			Args: "[a b a% 18 %x!_y% 1 2 true 1.5]",
+		},
+		{
+			V: &struct {
+				*filterPaging
+				Age *int `q:"age,gt"`
+			}{&filterPaging{Status: []string{}}, &age},
+			T:    []Table{T("user", "u")},
+			SQL:  `SELECT * FROM "user" WHERE ('IN' = '()')AND("u"."age" > ?)`,
+			Args: "[18]",
+		},
+		{
+			V: struct {
+				*filterPaging
+				Age *int `q:"age"`
+			}{},
+			SQL:  `SELECT * FROM "user"`,
+			Args: "[]",
+		},
+		{
+			V: &struct {
+				Name interface{} `q:"name,ilike"`
+				Age  interface{} `q:"age,in"`
+			}{"a", []int{1, 2}},
+			SQL:  `SELECT * FROM "user" WHERE (LOWER("name") LIKE LOWER(?))AND("age" IN (?,?))`,
+			Args: "[a 1 2]",
+		},
+	} {
+		got, args := Select().From(T("user")).Where(Filter(test.V, test.T...)...).ToSQL()
+		if got != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, got)
+		}
+		if a := fmt.Sprint(args); a != test.Args {
+			t.Errorf("tests[%d] want %s got %s", i, test.Args, a)
+		}
+	}
+}
+
+func TestFilterPanic(t *testing.T) {
+	for i, v := range []interface{}{
+		1,
+		(*testFilter)(nil),
+		struct {
+			A int `q:"a,unknown"`
+		}{},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("tests[%d] want panic", i)
+				}
+			}()
+			Filter(v)
+		}()
+	}
+}
+
+func TestMapFilter(t *testing.T) {
+	user := T("user", "u")
+	var nilPtr *int
+	age := 18
+	sql, args := Select().From(user).Where(MapFilter(map[Column]interface{}{
+		user.C("status"): []string{"a", "b"},
+		user.C("age"):    &age,
+		user.C("name"):   nil,
+		C("deleted"):     false,
+		user.C("score"):  nilPtr,
+		user.C("tags"):   []string(nil),
+	})...).ToSQL()
+	if want := `SELECT * FROM "user" AS "u" WHERE ("deleted" = ?)AND("u"."age" = ?)AND("u"."status" IN (?,?))`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if want, got := "[false 18 a b]", fmt.Sprint(args); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
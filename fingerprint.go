@@ -0,0 +1,33 @@
+package q
+
+import (
+	"hash/fnv"
+
+	"github.com/oov/q/qutil"
+)
+
+type fingerprintPlaceholder struct{}
+
+func (fingerprintPlaceholder) Next(buf []byte) []byte { return append(buf, '?') }
+
+// Fingerprint returns the normalized SQL of b and its hash.
+//
+// The normalized SQL doesn't depend on argument values,
+// so queries which have the same shape get the same result.
+// All placeholders are written as "?" regardless of the dialect,
+// and the list which is generated from InV or Eq(col, slice) is written as "(...)"
+// regardless of its length.
+func Fingerprint(b Builder) (string, uint64) {
+	d, cud := builderOptions(b)
+	if d == nil {
+		d = DefaultDialect
+	}
+	buf, ctx := qutil.NewContext(b, 128, 8, d)
+	ctx.CUD = cud
+	ctx.Normalize = true
+	ctx.Placeholder = fingerprintPlaceholder{}
+	buf = b.write(ctx, buf)
+	h := fnv.New64a()
+	h.Write(buf)
+	return string(buf), h.Sum64()
+}
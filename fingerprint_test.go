@@ -0,0 +1,59 @@
+package q
+
+import "testing"
+
+var fingerprintTests = []struct {
+	Name string
+	A, B Builder
+	Want string
+}{
+	{
+		Name: "different values",
+		A:    Select().From(T("user")).Where(Eq(C("id"), 1)),
+		B:    Select().From(T("user")).Where(Eq(C("id"), 2)),
+		Want: `SELECT * FROM "user" WHERE "id" = ?`,
+	},
+	{
+		Name: "different IN list length",
+		A:    Select().From(T("user")).Where(Eq(C("id"), []int{1})).Limit(10),
+		B:    Select().From(T("user")).Where(Eq(C("id"), []int{1, 2, 3})).Limit(20),
+		Want: `SELECT * FROM "user" WHERE "id" IN (...) LIMIT ?`,
+	},
+	{
+		Name: "InV",
+		A:    Delete(T("user")).Where(Unsafe(C("id"), " IN ", InV([]int{1, 2}))),
+		B:    Delete(T("user")).Where(Unsafe(C("id"), " IN ", InV([]int{3}))),
+		Want: `DELETE FROM "user" WHERE "id" IN (...)`,
+	},
+	{
+		Name: "PostgreSQL placeholder",
+		A:    Select().SetDialect(PostgreSQL).From(T("user")).Where(Eq(C("id"), []int{1}), Eq(C("age"), 18)),
+		B:    Select().SetDialect(PostgreSQL).From(T("user")).Where(Eq(C("id"), []int{1, 2}), Eq(C("age"), 20)),
+		Want: `SELECT * FROM "user" WHERE ("id" IN (...))AND("age" = ?)`,
+	},
+	{
+		Name: "MySQL quoted identifier",
+		A:    Update(T("user")).SetDialect(MySQL).Set(C("na?me"), "a").Where(Eq(C("id"), 1)),
+		B:    Update(T("user")).SetDialect(MySQL).Set(C("na?me"), "b").Where(Eq(C("id"), 2)),
+		Want: "UPDATE `user` SET `na?me` = ? WHERE `id` = ?",
+	},
+}
+
+func TestFingerprint(t *testing.T) {
+	for i, test := range fingerprintTests {
+		as, ah := Fingerprint(test.A)
+		bs, bh := Fingerprint(test.B)
+		if as != test.Want {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Want, as)
+		}
+		if as != bs || ah != bh {
+			t.Errorf("tests[%d] %s want same fingerprint got %s(%x) and %s(%x)", i, test.Name, as, ah, bs, bh)
+		}
+	}
+
+	_, ah := Fingerprint(Select().From(T("user")).Where(Eq(C("id"), 1)))
+	_, bh := Fingerprint(Select().From(T("user")).Where(Eq(C("name"), 1)))
+	if ah == bh {
+		t.Errorf("different queries want different hash got %x", ah)
+	}
+}
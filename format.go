@@ -0,0 +1,22 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// Format returns SQL and arguments like ToSQL, but the SQL is formatted for humans.
+//
+// Major clauses are written on separate lines,
+// and subqueries, joins, CASE arms and the operands of AND/OR are indented.
+// Placeholders and arguments are the same as the result of ToSQL.
+// It is useful to review the query plan or to write golden files,
+// while ToSQL keeps writing compact SQL for production use.
+func Format(b Builder) (string, []interface{}) {
+	d, cud := builderOptions(b)
+	if d == nil {
+		d = DefaultDialect
+	}
+	buf, ctx := qutil.NewContext(b, 256, 8, d)
+	ctx.CUD = cud
+	ctx.Format = true
+	buf = b.write(ctx, buf)
+	return string(buf), ctx.Args
+}
@@ -0,0 +1,100 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+)
+
+var formatTests = []struct {
+	Name string
+	B    Builder
+	SQL  string
+	Args string
+}{
+	{
+		Name: "select",
+		B: func() Builder {
+			user, post := T("user", "u"), T("post", "p")
+			return Select().From(
+				user.InnerJoin(post, Eq(post.C("user_id"), user.C("id"))).LeftJoin(T("tag", "t"), Eq(T("tag", "t").C("post_id"), post.C("id"))),
+			).Column(
+				user.C("name"),
+				Case().When(Gte(user.C("age"), 20), "adult").Else("child").C("generation"),
+			).Where(
+				Or(Eq(user.C("id"), 1), And(Gt(user.C("age"), 10), Lt(user.C("age"), 20))),
+				In(user.C("id"), Select().Column(post.C("user_id")).From(post).Where(Eq(post.C("draft"), false))),
+			).GroupBy(user.C("name")).Having(Gt(Count(post.C("id")), 1)).OrderBy(user.C("name"), true).Limit(10).Offset(20)
+		}(),
+		SQL: `SELECT "u"."name", CASE
+  WHEN "u"."age" >= ? THEN ?
+  ELSE ?
+END AS "generation"
+FROM "user" AS "u"
+  INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id"
+  LEFT JOIN "tag" AS "t" ON "t"."post_id" = "p"."id"
+WHERE (("u"."id" = ?)
+    OR (("u"."age" > ?)
+      AND ("u"."age" < ?)))
+  AND ("u"."id" IN (
+    SELECT "p"."user_id"
+    FROM "post" AS "p"
+    WHERE "p"."draft" = ?
+  ))
+GROUP BY "u"."name"
+HAVING COUNT("p"."id") > ?
+ORDER BY "u"."name" ASC
+LIMIT ?
+OFFSET ?`,
+		Args: `[20 adult child 1 10 20 false 1 10 20]`,
+	},
+	{
+		Name: "subquery table",
+		B:    Select().From(Select().From(T("user")).T("s")).SetDialect(PostgreSQL),
+		SQL: `SELECT *
+FROM (
+  SELECT *
+  FROM "user"
+) AS "s"`,
+		Args: `[]`,
+	},
+	{
+		Name: "insert",
+		B:    Insert().Into(T("user")).Set(C("name"), "a").Set(C("age"), 1).Returning(C("id")).SetDialect(PostgreSQL),
+		SQL: `INSERT INTO "user"("name", "age")
+VALUES ($1, $2)
+RETURNING "id"`,
+		Args: `[a 1]`,
+	},
+	{
+		Name: "update",
+		B:    Update(T("user")).Set(C("name"), "a").Where(Eq(C("id"), 1), Eq(C("deleted"), false)).SetDialect(MySQL),
+		SQL:  "UPDATE `user`\nSET `name` = ?\nWHERE (`id` = ?)\n  AND (`deleted` = ?)",
+		Args: `[a 1 false]`,
+	},
+	{
+		Name: "delete",
+		B:    Delete(T("user")).Where(Eq(C("id"), 1)),
+		SQL: `DELETE FROM "user"
+WHERE "id" = ?`,
+		Args: `[1]`,
+	},
+}
+
+func TestFormat(t *testing.T) {
+	for i, test := range formatTests {
+		compact, compactArgs := test.B.ToSQL()
+		sql, args := Format(test.B)
+		if sql != test.SQL {
+			t.Errorf("tests[%d] %s want\n%s\ngot\n%s", i, test.Name, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+		if want, got := fmt.Sprint(compactArgs), fmt.Sprint(args); want != got {
+			t.Errorf("tests[%d] %s args differ from ToSQL: want %s got %s", i, test.Name, want, got)
+		}
+		if again, _ := test.B.ToSQL(); again != compact {
+			t.Errorf("tests[%d] %s Format changed ToSQL: want %s got %s", i, test.Name, compact, again)
+		}
+	}
+}
@@ -73,3 +73,99 @@ func CharLength(v interface{}) Function {
 func Now() Function {
 	return Unsafe(`CURRENT_TIMESTAMP`)
 }
+
+type variadicFunc struct {
+	Name   string
+	Values []interface{}
+}
+
+func (f *variadicFunc) String() string               { return expressionToString(f) }
+func (f *variadicFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *variadicFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return writeFunc(ctx, buf, f.Name, f.Values)
+}
+
+func writeFunc(ctx *qutil.Context, buf []byte, name string, vs []interface{}) []byte {
+	buf = append(buf, name...)
+	buf = append(buf, '(')
+	for i, v := range vs {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = writeIntf(v, ctx, buf)
+	}
+	return append(buf, ')')
+}
+
+// Coalesce creates Function such as "COALESCE(v[0], v[1])",
+// which returns the first value which is not NULL.
+func Coalesce(v ...interface{}) Function {
+	if len(v) == 0 {
+		panic("q: need at least one value for COALESCE.")
+	}
+	return &variadicFunc{"COALESCE", v}
+}
+
+// NullIf creates Function such as "NULLIF(v1, v2)",
+// which returns NULL if v1 equals v2, otherwise returns v1.
+func NullIf(v1, v2 interface{}) Function {
+	return &variadicFunc{"NULLIF", []interface{}{v1, v2}}
+}
+
+type greatestFunc struct {
+	Least  bool
+	Values []interface{}
+}
+
+func (f *greatestFunc) String() string               { return expressionToString(f) }
+func (f *greatestFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *greatestFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if len(f.Values) == 1 {
+		// MAX and MIN with one argument are the aggregate functions in SQLite,
+		// and MySQL doesn't accept GREATEST and LEAST with one argument.
+		return writeIntf(operand(f.Values[0], precUnary, false), ctx, buf)
+	}
+	if f.Least {
+		return writeFunc(ctx, buf, ctx.Dialect.LeastName(), f.Values)
+	}
+	return writeFunc(ctx, buf, ctx.Dialect.GreatestName(), f.Values)
+}
+
+// Greatest creates Function such as "GREATEST(v[0], v[1])".
+// In SQLite, it is written as "MAX(v[0], v[1])".
+// If only one value is given, it is written as the value itself.
+// Note that PostgreSQL ignores NULLs, but MySQL and SQLite return NULL if any value is NULL.
+func Greatest(v ...interface{}) Function {
+	if len(v) == 0 {
+		panic("q: need at least one value for GREATEST.")
+	}
+	return &greatestFunc{Values: v}
+}
+
+// Least creates Function such as "LEAST(v[0], v[1])".
+// In SQLite, it is written as "MIN(v[0], v[1])".
+// If only one value is given, it is written as the value itself.
+// Note that PostgreSQL ignores NULLs, but MySQL and SQLite return NULL if any value is NULL.
+func Least(v ...interface{}) Function {
+	if len(v) == 0 {
+		panic("q: need at least one value for LEAST.")
+	}
+	return &greatestFunc{Least: true, Values: v}
+}
+
+type ifFunc struct {
+	Cond, Then, Else interface{}
+}
+
+func (f *ifFunc) String() string               { return expressionToString(f) }
+func (f *ifFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *ifFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.If(ctx, buf, f.Cond, f.Then, f.Else)
+}
+
+// If creates Function which returns then if cond is true, otherwise returns els.
+// It is written as "IF(cond, then, els)" in MySQL, "IIF(cond, then, els)" in SQLite
+// and "CASE WHEN cond THEN then ELSE els END" in the other dialects.
+func If(cond Expression, then, els interface{}) Function {
+	return &ifFunc{Cond: cond, Then: then, Else: els}
+}
@@ -2,6 +2,7 @@ package q
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/oov/q/qutil"
@@ -30,3 +31,92 @@ func TestCurrentTimestampOnDB(t *testing.T) {
 		}
 	}
 }
+
+func TestConditionalFunc(t *testing.T) {
+	x, y := C("x"), C("y")
+	for i, test := range []struct {
+		F                               Function
+		MySQL, PostgreSQL, SQLite, Args string
+	}{
+		{
+			F:          Coalesce(x, y, 0),
+			MySQL:      "COALESCE(`x`, `y`, ?)",
+			PostgreSQL: `COALESCE("x", "y", $1)`,
+			SQLite:     `COALESCE("x", "y", ?)`,
+			Args:       "[0]",
+		},
+		{
+			F:          NullIf(x, ""),
+			MySQL:      "NULLIF(`x`, ?)",
+			PostgreSQL: `NULLIF("x", $1)`,
+			SQLite:     `NULLIF("x", ?)`,
+			Args:       "[]",
+		},
+		{
+			F:          Greatest(x, y, 1),
+			MySQL:      "GREATEST(`x`, `y`, ?)",
+			PostgreSQL: `GREATEST("x", "y", $1)`,
+			SQLite:     `MAX("x", "y", ?)`,
+			Args:       "[1]",
+		},
+		{
+			F:          Least(x, Add(y, 1).C()),
+			MySQL:      "LEAST(`x`, `y` + ?)",
+			PostgreSQL: `LEAST("x", "y" + $1)`,
+			SQLite:     `MIN("x", "y" + ?)`,
+			Args:       "[1]",
+		},
+		{
+			F:          Greatest(x),
+			MySQL:      "`x`",
+			PostgreSQL: `"x"`,
+			SQLite:     `"x"`,
+			Args:       "[]",
+		},
+		{
+			F:          Least(Add(y, 1)),
+			MySQL:      "(`y` + ?)",
+			PostgreSQL: `("y" + $1)`,
+			SQLite:     `("y" + ?)`,
+			Args:       "[1]",
+		},
+		{
+			F:          If(Gt(x, 0), "positive", Coalesce(y, nil)),
+			MySQL:      "IF(`x` > ?, ?, COALESCE(`y`, NULL))",
+			PostgreSQL: `CASE WHEN "x" > $1 THEN $2 ELSE COALESCE("y", NULL) END`,
+			SQLite:     `IIF("x" > ?, ?, COALESCE("y", NULL))`,
+			Args:       "[0 positive]",
+		},
+	} {
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{
+			{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}, {SQLite, test.SQLite},
+		} {
+			sql, args := Select().Column(test.F.C()).SetDialect(d.D).ToSQL()
+			if want := "SELECT " + d.Want; sql != want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, want, sql)
+			}
+			if got := fmt.Sprint(args); got != test.Args {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, test.Args, got)
+			}
+		}
+		if got, want := fmt.Sprint(clone(test.F)), fmt.Sprint(test.F); got != want {
+			t.Errorf("tests[%d] clone want %s got %s", i, want, got)
+		}
+	}
+}
+
+func TestConditionalFuncPanic(t *testing.T) {
+	for i, f := range []func(...interface{}) Function{Coalesce, Greatest, Least} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("tests[%d] want panic", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -152,14 +152,16 @@ func (e Z{{.Name}}Expr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
 	case 1:
 		return e[0].WriteExpression(ctx, buf)
 	}
+	ctx.Indent++
 	buf = append(buf, '(')
 	buf = e[0].WriteExpression(ctx, buf)
 	buf = append(buf, ')')
 	for _, cd := range e[1:] {
-		buf = append(buf, "{{.Op}}("...)
+		buf = writeLogicalOperator(ctx, buf, "{{.Op}}")
 		buf = cd.WriteExpression(ctx, buf)
 		buf = append(buf, ')')
 	}
+	ctx.Indent--
 	return buf
 }
 {{end}}
@@ -15,10 +15,13 @@ func main() {
 	vars := []string{
 		"Year",
 		"Month",
+		"Week",
 		"Day",
 		"Hour",
 		"Minute",
 		"Second",
+		"Millisecond",
+		"Microsecond",
 	}
 
 	funcMap := template.FuncMap{
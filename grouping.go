@@ -0,0 +1,103 @@
+package q
+
+import (
+	"fmt"
+
+	"github.com/oov/q/qutil"
+)
+
+// Rollup creates Expression such as "ROLLUP (exprs[0], exprs[1])" for GROUP BY clause.
+//
+// In MySQL, it is written as "GROUP BY exprs[0], exprs[1] WITH ROLLUP",
+// so it must be the only element of GROUP BY clause.
+func Rollup(exprs ...Expression) Expression {
+	return &groupingSetExpr{Name: "ROLLUP", Sets: [][]Expression{exprs}}
+}
+
+// Cube creates Expression such as "CUBE (exprs[0], exprs[1])" for GROUP BY clause.
+// It is not supported in MySQL.
+func Cube(exprs ...Expression) Expression {
+	return &groupingSetExpr{Name: "CUBE", Sets: [][]Expression{exprs}}
+}
+
+// GroupingSets creates Expression such as "GROUPING SETS ((sets[0][0], sets[0][1]), (sets[1][0]), ())"
+// for GROUP BY clause. It is not supported in MySQL.
+func GroupingSets(sets ...[]Expression) Expression {
+	return &groupingSetExpr{Name: "GROUPING SETS", Sets: sets}
+}
+
+// Grouping creates Function such as "GROUPING(exprs[0], exprs[1])",
+// which tells whether the expressions are aggregated in the row of subtotals.
+func Grouping(exprs ...Expression) Function {
+	return &function{"GROUPING", Unsafe(joinExprs(exprs)...)}
+}
+
+// joinExprs returns exprs with the separator ", " for Unsafe.
+func joinExprs(exprs []Expression) []interface{} {
+	r := make([]interface{}, 0, len(exprs)*2)
+	for i, e := range exprs {
+		if i > 0 {
+			r = append(r, ", ")
+		}
+		r = append(r, e)
+	}
+	return r
+}
+
+type groupingSetExpr struct {
+	Name string
+	Sets [][]Expression
+}
+
+func (e *groupingSetExpr) String() string               { return expressionToString(e) }
+func (e *groupingSetExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *groupingSetExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if !ctx.Dialect.CanUseGroupingSets() {
+		setError(ctx, fmt.Errorf("q: %s is not supported in %v", e.Name, ctx.Dialect))
+	}
+	buf = append(buf, e.Name...)
+	buf = append(buf, " ("...)
+	if e.Name != "GROUPING SETS" {
+		buf = writeExprs(ctx, buf, e.Sets[0])
+		return append(buf, ')')
+	}
+	for i, set := range e.Sets {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, '(')
+		buf = writeExprs(ctx, buf, set)
+		buf = append(buf, ')')
+	}
+	return append(buf, ')')
+}
+
+func writeExprs(ctx *qutil.Context, buf []byte, exprs []Expression) []byte {
+	for i, e := range exprs {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = e.WriteExpression(ctx, buf)
+	}
+	return buf
+}
+
+// writeGroups writes the elements of GROUP BY clause.
+// In MySQL, ROLLUP is written as "a, b WITH ROLLUP".
+func writeGroups(ctx *qutil.Context, buf []byte, groups []Expression) []byte {
+	if ctx.Dialect.CanUseWithRollup() {
+		for _, g := range groups {
+			gs, ok := g.(*groupingSetExpr)
+			if !ok || gs.Name != "ROLLUP" {
+				continue
+			}
+			if len(groups) != 1 {
+				setError(ctx, fmt.Errorf("q: ROLLUP must be the only element of GROUP BY clause in %v", ctx.Dialect))
+				break
+			}
+			buf = writeExprs(ctx, buf, gs.Sets[0])
+			return append(buf, " WITH ROLLUP"...)
+		}
+	}
+	return writeExprs(ctx, buf, groups)
+}
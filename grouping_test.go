@@ -0,0 +1,118 @@
+package q
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestGrouping(t *testing.T) {
+	sale := T("sale", "s")
+	year, region := sale.C("year"), sale.C("region")
+	for i, test := range []struct {
+		B    *ZSelectBuilder
+		D    qutil.Dialect
+		SQL  string
+		Args string
+		Err  string
+	}{
+		{
+			B: Select().Column(year, region, Sum(sale.C("amount")).C("total"), Grouping(year, region).C("g")).
+				From(sale).GroupBy(Rollup(year, region)),
+			D:    PostgreSQL,
+			SQL:  `SELECT "s"."year", "s"."region", SUM("s"."amount") AS "total", GROUPING("s"."year", "s"."region") AS "g" FROM "sale" AS "s" GROUP BY ROLLUP ("s"."year", "s"."region")`,
+			Args: "[]",
+		},
+		{
+			B: Select().Column(year, region, Sum(sale.C("amount")).C("total"), Grouping(year, region).C("g")).
+				From(sale).GroupBy(Rollup(year, region)),
+			D:    MySQL,
+			SQL:  "SELECT `s`.`year`, `s`.`region`, SUM(`s`.`amount`) AS `total`, GROUPING(`s`.`year`, `s`.`region`) AS `g` FROM `sale` AS `s` GROUP BY `s`.`year`, `s`.`region` WITH ROLLUP",
+			Args: "[]",
+		},
+		{
+			B:    Select().Column(year, region).From(sale).GroupBy(Cube(year, region)),
+			D:    PostgreSQL,
+			SQL:  `SELECT "s"."year", "s"."region" FROM "sale" AS "s" GROUP BY CUBE ("s"."year", "s"."region")`,
+			Args: "[]",
+		},
+		{
+			B:    Select().Column(year, region).From(sale).GroupBy(GroupingSets([]Expression{year, region}, []Expression{year}, nil)),
+			D:    PostgreSQL,
+			SQL:  `SELECT "s"."year", "s"."region" FROM "sale" AS "s" GROUP BY GROUPING SETS (("s"."year", "s"."region"), ("s"."year"), ())`,
+			Args: "[]",
+		},
+		{
+			B:    Select().Column(year, region).From(sale).GroupBy(year, Rollup(region)),
+			D:    PostgreSQL,
+			SQL:  `SELECT "s"."year", "s"."region" FROM "sale" AS "s" GROUP BY "s"."year", ROLLUP ("s"."region")`,
+			Args: "[]",
+		},
+		{
+			B:   Select().Column(year, region).From(sale).GroupBy(year, Rollup(region)),
+			D:   MySQL,
+			SQL: "SELECT `s`.`year`, `s`.`region` FROM `sale` AS `s` GROUP BY `s`.`year`, ROLLUP (`s`.`region`)",
+			Err: "q: ROLLUP must be the only element of GROUP BY clause in MySQL",
+		},
+		{
+			B:   Select().Column(year, region).From(sale).GroupBy(Cube(year, region)),
+			D:   MySQL,
+			SQL: "SELECT `s`.`year`, `s`.`region` FROM `sale` AS `s` GROUP BY CUBE (`s`.`year`, `s`.`region`)",
+			Err: "q: CUBE is not supported in MySQL",
+		},
+		{
+			B:   Select().Column(year).From(sale).GroupBy(Rollup(year)),
+			D:   SQLite,
+			SQL: `SELECT "s"."year" FROM "sale" AS "s" GROUP BY ROLLUP ("s"."year")`,
+			Err: "q: ROLLUP is not supported in SQLite",
+		},
+		{
+			B:   Select().Column(year).From(sale).GroupBy(GroupingSets([]Expression{year})),
+			D:   SQLite,
+			SQL: `SELECT "s"."year" FROM "sale" AS "s" GROUP BY GROUPING SETS (("s"."year"))`,
+			Err: "q: GROUPING SETS is not supported in SQLite",
+		},
+	} {
+		test.B.SetDialect(test.D)
+		sql, args := test.B.ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, sql)
+		}
+		if test.Args != "" {
+			if got := fmt.Sprint(args); got != test.Args {
+				t.Errorf("tests[%d] want %s got %s", i, test.Args, got)
+			}
+		}
+		err := Validate(test.B)
+		if test.Err == "" && err != nil {
+			t.Errorf("tests[%d] want nil got %v", i, err)
+		}
+		if test.Err != "" && (err == nil || err.Error() != test.Err) {
+			t.Errorf("tests[%d] want %s got %v", i, test.Err, err)
+		}
+		if got := clone(test.B).(*ZSelectBuilder).String(); got != test.B.String() {
+			t.Errorf("tests[%d] clone want %s got %s", i, test.B.String(), got)
+		}
+	}
+}
+
+func TestExecutorValidate(t *testing.T) {
+	db, d := openFakeDB(t)
+	defer db.Close()
+
+	var log []string
+	e := Executor(db, &recordHook{Name: "1", Log: &log})
+	b := Select().From(T("sale")).GroupBy(Cube(C("year"))).SetDialect(SQLite)
+	if _, err := e.Query(context.Background(), b); err == nil || !strings.Contains(err.Error(), "CUBE") {
+		t.Errorf("want error got %v", err)
+	}
+	if _, err := e.Exec(context.Background(), b); err == nil {
+		t.Errorf("want error got nil")
+	}
+	if len(log) != 0 || len(d.Queries) != 0 {
+		t.Errorf("want no execution got %v %v", log, d.Queries)
+	}
+}
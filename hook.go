@@ -0,0 +1,48 @@
+package q
+
+import "context"
+
+// Tracer represents a tracer such as OpenTelemetry's trace.Tracer.
+// It is used by TracerHook and it can be implemented as a thin adapter.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span represents a span which is created by Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// spanKey is the key of the span in the context, which is distinguished by the hook.
+type spanKey struct {
+	Hook *tracerHook
+}
+
+type tracerHook struct {
+	Tracer Tracer
+}
+
+func (h *tracerHook) Before(ctx context.Context, e *HookEvent) context.Context {
+	ctx, span := h.Tracer.Start(ctx, "q."+e.Kind)
+	span.SetAttribute("db.statement", e.SQL)
+	return context.WithValue(ctx, spanKey{h}, span)
+}
+
+func (h *tracerHook) After(ctx context.Context, e *HookEvent) {
+	span, ok := ctx.Value(spanKey{h}).(Span)
+	if !ok {
+		return
+	}
+	span.SetAttribute("db.rows_affected", e.RowsAffected)
+	if e.Err != nil {
+		span.RecordError(e.Err)
+	}
+	span.End()
+}
+
+// TracerHook creates Hook which starts a span for each statement.
+func TracerHook(t Tracer) Hook {
+	return &tracerHook{Tracer: t}
+}
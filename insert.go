@@ -34,6 +34,11 @@ func (b *ZInsertBuilder) SetDialect(d qutil.Dialect) *ZInsertBuilder {
 	return b
 }
 
+// Clone returns a deep copy of the builder.
+func (b *ZInsertBuilder) Clone() *ZInsertBuilder {
+	return clone(b).(*ZInsertBuilder)
+}
+
 // Into sets a table to the builder.
 func (b *ZInsertBuilder) Into(table Table) *ZInsertBuilder {
 	b.Table = table
@@ -41,9 +46,7 @@ func (b *ZInsertBuilder) Into(table Table) *ZInsertBuilder {
 }
 
 func (b *ZInsertBuilder) find(c Column) (int, string) {
-	buf, ctx := qutil.NewContext(b, 32, 0, nil)
-	ctx.CUD = true
-	name := string(c.WriteColumn(ctx, buf))
+	name := cudColumnName(c)
 	for i, s := range b.Sets {
 		if name == s.Name {
 			return i, name
@@ -110,7 +113,9 @@ func (b *ZInsertBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 		buf = append(buf, ", "...)
 		buf = s.Column.WriteColumn(ctx, buf)
 	}
-	buf = append(buf, ") VALUES ("...)
+	buf = append(buf, ')')
+	buf = writeSeparator(ctx, buf)
+	buf = append(buf, "VALUES ("...)
 	buf = b.Sets[0].Expression.WriteExpression(ctx, buf)
 	for _, s := range b.Sets[1:] {
 		buf = append(buf, ", "...)
@@ -119,7 +124,8 @@ func (b *ZInsertBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 	buf = append(buf, ')')
 
 	if len(b.Returnings) > 0 && ctx.Dialect.CanUseReturning() {
-		buf = append(buf, " RETURNING "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "RETURNING "...)
 		buf = b.Returnings[0].WriteDefinition(ctx, buf)
 		for _, c := range b.Returnings[1:] {
 			buf = append(buf, ", "...)
@@ -173,6 +173,14 @@ func TestInsert(t *testing.T) {
 	}
 }
 
+func TestInsertClone(t *testing.T) {
+	for i, test := range insertTests {
+		if r := fmt.Sprint(test.B.Clone()); r != fmt.Sprint(test.V) {
+			t.Errorf("test[%d] %s want %v got %v", i, test.Name, test.V, r)
+		}
+	}
+}
+
 func TestInsertOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
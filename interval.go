@@ -2,7 +2,11 @@
 
 package q
 
-import "github.com/oov/q/qutil"
+import (
+	"time"
+
+	"github.com/oov/q/qutil"
+)
 
 // Interval represents intervals in SQL statements.
 type Interval interface {
@@ -29,3 +33,59 @@ func AddInterval(v interface{}, intervals ...Interval) Function {
 	}
 	return &addIntervalFunc{V: v, Intervals: ivs}
 }
+
+// exprInterval represents the interval whose quantity is an expression or an argument.
+type exprInterval struct {
+	Q interface{}
+	U qutil.IntervalUnit
+	N int // 1, or -1 if the interval is negated.
+}
+
+func (i *exprInterval) Value() int               { return i.N }
+func (i *exprInterval) Unit() qutil.IntervalUnit { return i.U }
+func (i *exprInterval) Quantity() interface{}    { return i.Q }
+
+// IntervalOf creates Interval whose quantity is v, such as "INTERVAL v DAY".
+// v can be an Expression such as a column, or a value which is passed as an argument,
+// so the quantity can be replaced by ZArgsBuilder if v is Variable.
+func IntervalOf(v interface{}, unit qutil.IntervalUnit) Interval {
+	return &exprInterval{Q: operand(v, precUnary, false), U: unit, N: 1}
+}
+
+// Duration creates Interval from d, such as "INTERVAL 90 MINUTE" for 90 * time.Minute.
+// The largest unit from Hour to Microsecond which represents d exactly is used,
+// and the part which is smaller than a microsecond is truncated.
+func Duration(d time.Duration) Interval {
+	switch {
+	case d%time.Hour == 0:
+		return Hours(int(d / time.Hour))
+	case d%time.Minute == 0:
+		return Minutes(int(d / time.Minute))
+	case d%time.Second == 0:
+		return Seconds(int(d / time.Second))
+	case d%time.Millisecond == 0:
+		return Milliseconds(int(d / time.Millisecond))
+	}
+	return Microseconds(int(d / time.Microsecond))
+}
+
+// negativeInterval represents the interval whose value is negated.
+type negativeInterval struct {
+	qutil.Interval
+}
+
+func (i negativeInterval) Value() int { return -i.Interval.Value() }
+
+// SubInterval creates Function such as "v - INTERVAL intervals[n] YEAR - ...".
+// It is written as AddInterval with the negative intervals, such as "v + INTERVAL -1 YEAR".
+func SubInterval(v interface{}, intervals ...Interval) Function {
+	ivs := make([]qutil.Interval, len(intervals))
+	for i, v := range intervals {
+		if e, ok := v.(qutil.ExpressionInterval); ok {
+			ivs[i] = &exprInterval{Q: e.Quantity(), U: e.Unit(), N: -e.Value()}
+		} else {
+			ivs[i] = negativeInterval{v}
+		}
+	}
+	return &addIntervalFunc{V: v, Intervals: ivs}
+}
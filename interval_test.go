@@ -87,6 +87,24 @@ var intervalTests = []struct {
 		Want: `"at" + INTERVAL 2 SECOND []`,
 		V:    resultMap("SELECT `at` + INTERVAL 2 SECOND AS `i` []", `SELECT "at" + INTERVAL '2 seconds' AS "i" []`, `SELECT DATETIME("at", '2 seconds') AS "i" []`),
 	},
+	{
+		Name: `2 Weeks`,
+		E:    AddInterval(C("at"), Weeks(2)),
+		Want: `"at" + INTERVAL 2 WEEK []`,
+		V:    resultMap("SELECT `at` + INTERVAL 2 WEEK AS `i` []", `SELECT "at" + INTERVAL '2 weeks' AS "i" []`, `SELECT DATETIME("at", '14 days') AS "i" []`),
+	},
+	{
+		Name: `1500 Milliseconds`,
+		E:    AddInterval(C("at"), Milliseconds(1500)),
+		Want: `"at" + INTERVAL 1500000 MICROSECOND []`,
+		V:    resultMap("SELECT `at` + INTERVAL 1500000 MICROSECOND AS `i` []", `SELECT "at" + INTERVAL '1500 milliseconds' AS "i" []`, `SELECT STRFTIME('%Y-%m-%d %H:%M:%f', "at", '1.500 seconds') AS "i" []`),
+	},
+	{
+		Name: `2 Microseconds`,
+		E:    AddInterval(C("at"), Microseconds(2)),
+		Want: `"at" + INTERVAL 2 MICROSECOND []`,
+		V:    resultMap("SELECT `at` + INTERVAL 2 MICROSECOND AS `i` []", `SELECT "at" + INTERVAL '2 microseconds' AS "i" []`, `SELECT STRFTIME('%Y-%m-%d %H:%M:%f', "at", '0.000002 seconds') AS "i" []`),
+	},
 }
 
 func TestInterval(t *testing.T) {
@@ -102,6 +120,140 @@ func TestInterval(t *testing.T) {
 	}
 }
 
+func TestIntervalOf(t *testing.T) {
+	at, n := C("at"), C("n")
+	for i, test := range []struct {
+		E                               Expression
+		MySQL, PostgreSQL, SQLite, Fake string
+	}{
+		{
+			E:          AddInterval(at, IntervalOf(n, Day)),
+			MySQL:      "`at` + INTERVAL `n` DAY []",
+			PostgreSQL: `"at" + "n" * INTERVAL '1 day' []`,
+			SQLite:     `DATETIME("at", "n" || ' days') []`,
+			Fake:       `"at" + INTERVAL "n" DAY []`,
+		},
+		{
+			E:          AddInterval(at, IntervalOf(3, Hour), Months(1)),
+			MySQL:      "`at` + INTERVAL ? HOUR + INTERVAL 1 MONTH [3]",
+			PostgreSQL: `"at" + $1 * INTERVAL '1 hour' + INTERVAL '1 month' [3]`,
+			SQLite:     `DATETIME("at", ? || ' hours', '1 month') [3]`,
+			Fake:       `"at" + INTERVAL ? HOUR + INTERVAL 1 MONTH [3]`,
+		},
+		{
+			E:          AddInterval(at, IntervalOf(Add(n, 1), Week)),
+			MySQL:      "`at` + INTERVAL (`n` + ?) WEEK [1]",
+			PostgreSQL: `"at" + ("n" + $1) * INTERVAL '1 week' [1]`,
+			SQLite:     `DATETIME("at", (("n" + ?) * 7) || ' days') [1]`,
+			Fake:       `"at" + INTERVAL ("n" + ?) WEEK [1]`,
+		},
+		{
+			E:          AddInterval(at, IntervalOf(n, Millisecond)),
+			MySQL:      "`at` + INTERVAL `n` * 1000 MICROSECOND []",
+			PostgreSQL: `"at" + "n" * INTERVAL '1 millisecond' []`,
+			SQLite:     `STRFTIME('%Y-%m-%d %H:%M:%f', "at", ("n" / 1000.0) || ' seconds') []`,
+			Fake:       `"at" + INTERVAL "n" * 1000 MICROSECOND []`,
+		},
+		{
+			E:          SubInterval(at, IntervalOf(n, Day), Days(1)),
+			MySQL:      "`at` + INTERVAL `n` * -1 DAY + INTERVAL -1 DAY []",
+			PostgreSQL: `"at" + "n" * INTERVAL '-1 days' + INTERVAL '-1 days' []`,
+			SQLite:     `DATETIME("at", ("n" * -1) || ' days', '-1 days') []`,
+			Fake:       `"at" + INTERVAL "n" * -1 DAY + INTERVAL -1 DAY []`,
+		},
+		{
+			E:          SubInterval(at, IntervalOf(n, Microsecond)),
+			MySQL:      "`at` + INTERVAL `n` * -1 MICROSECOND []",
+			PostgreSQL: `"at" + "n" * INTERVAL '-1 microseconds' []`,
+			SQLite:     `STRFTIME('%Y-%m-%d %H:%M:%f', "at", ("n" * -1 / 1000000.0) || ' seconds') []`,
+			Fake:       `"at" + INTERVAL "n" * -1 MICROSECOND []`,
+		},
+		{
+			E:          AddInterval(at, Days(1), Milliseconds(250)),
+			MySQL:      "`at` + INTERVAL 1 DAY + INTERVAL 250000 MICROSECOND []",
+			PostgreSQL: `"at" + INTERVAL '1 day' + INTERVAL '250 milliseconds' []`,
+			SQLite:     `STRFTIME('%Y-%m-%d %H:%M:%f', "at", '1 day', '0.250 seconds') []`,
+			Fake:       `"at" + INTERVAL 1 DAY + INTERVAL 250000 MICROSECOND []`,
+		},
+		{
+			E:          AddInterval(at, Days(1), Milliseconds(0)),
+			MySQL:      "`at` + INTERVAL 1 DAY []",
+			PostgreSQL: `"at" + INTERVAL '1 day' []`,
+			SQLite:     `DATETIME("at", '1 day') []`,
+			Fake:       `"at" + INTERVAL 1 DAY []`,
+		},
+	} {
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{
+			{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}, {SQLite, test.SQLite}, {nil, test.Fake},
+		} {
+			sql, args := Select().Column(test.E.C()).SetDialect(d.D).ToSQL()
+			if got, want := fmt.Sprint(sql, " ", args), "SELECT "+d.Want; got != want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, want, got)
+			}
+		}
+		if got, want := fmt.Sprint(clone(test.E)), fmt.Sprint(test.E); got != want {
+			t.Errorf("tests[%d] clone want %s got %s", i, want, got)
+		}
+	}
+}
+
+func TestIntervalOfArgs(t *testing.T) {
+	e := AddInterval(C("at"), IntervalOf(V(3, "days"), Day))
+	sql, gen := Select().SetDialect(SQLite).Column(e.C("i")).ToPrepared()
+	if want := `SELECT DATETIME("at", ? || ' days') AS "i"`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	ab := gen()
+	ab.Set("days", 7)
+	if r := fmt.Sprint(ab.Args); r != "[7]" {
+		t.Errorf("want [7] got %s", r)
+	}
+
+	found := false
+	Walk(e, func(x interface{}) bool {
+		if _, ok := x.(Variable); ok {
+			found = true
+		}
+		return true
+	})
+	if !found {
+		t.Errorf("Walk doesn't visit the quantity of the interval")
+	}
+}
+
+func TestIntervalValidate(t *testing.T) {
+	e := AddInterval(C("at"), Days(1), IntervalOf(C("n"), qutil.IntervalUnit(99)))
+	for _, d := range []qutil.Dialect{MySQL, PostgreSQL, SQLite, nil} {
+		if err := Validate(Select().Column(e.C()).SetDialect(d)); err == nil {
+			t.Errorf("%v want error got nil", d)
+		}
+	}
+	if err := Validate(Select().Column(AddInterval(C("at"), Microseconds(1)).C()).SetDialect(SQLite)); err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for i, test := range []struct {
+		D    time.Duration
+		Want string
+	}{
+		{2 * time.Hour, `"at" + INTERVAL 2 HOUR []`},
+		{90 * time.Minute, `"at" + INTERVAL 90 MINUTE []`},
+		{-5 * time.Second, `"at" + INTERVAL -5 SECOND []`},
+		{1500 * time.Millisecond, `"at" + INTERVAL 1500000 MICROSECOND []`},
+		{1500*time.Microsecond + 1, `"at" + INTERVAL 1500 MICROSECOND []`},
+		{0, `"at" []`},
+	} {
+		if r := fmt.Sprint(AddInterval(C("at"), Duration(test.D))); r != test.Want {
+			t.Errorf("test[%d] want %s got %s", i, test.Want, r)
+		}
+	}
+}
+
 func TestIntervalOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
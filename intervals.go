@@ -18,6 +18,14 @@ func (i months) Unit() qutil.IntervalUnit { return qutil.Month }
 // Months creates Interval such as "INTERVAL n MONTH".
 func Months(n int) Interval { return months(n) }
 
+type weeks int
+
+func (i weeks) Value() int               { return int(i) }
+func (i weeks) Unit() qutil.IntervalUnit { return qutil.Week }
+
+// Weeks creates Interval such as "INTERVAL n WEEK".
+func Weeks(n int) Interval { return weeks(n) }
+
 type days int
 
 func (i days) Value() int               { return int(i) }
@@ -49,3 +57,19 @@ func (i seconds) Unit() qutil.IntervalUnit { return qutil.Second }
 
 // Seconds creates Interval such as "INTERVAL n SECOND".
 func Seconds(n int) Interval { return seconds(n) }
+
+type milliseconds int
+
+func (i milliseconds) Value() int               { return int(i) }
+func (i milliseconds) Unit() qutil.IntervalUnit { return qutil.Millisecond }
+
+// Milliseconds creates Interval such as "INTERVAL n MILLISECOND".
+func Milliseconds(n int) Interval { return milliseconds(n) }
+
+type microseconds int
+
+func (i microseconds) Value() int               { return int(i) }
+func (i microseconds) Unit() qutil.IntervalUnit { return qutil.Microsecond }
+
+// Microseconds creates Interval such as "INTERVAL n MICROSECOND".
+func Microseconds(n int) Interval { return microseconds(n) }
@@ -0,0 +1,92 @@
+package q
+
+import (
+	"strings"
+
+	"github.com/oov/q/qutil"
+)
+
+// LikeEscapeChar is the escape character which is used by EscapeLike.
+const LikeEscapeChar = '!'
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// EscapeLike escapes the wildcard characters "%" and "_" in s by LikeEscapeChar,
+// so that s matches literally in the LIKE pattern.
+// The pattern which contains the escaped string must be used with the ESCAPE clause,
+// so use Contains, StartsWith and EndsWith if possible.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Like creates Expression such as "l LIKE r".
+//
+// r is used as the pattern as is, so the wildcard characters in r are not escaped.
+// If you want to search the input from outside, use Contains, StartsWith or EndsWith instead.
+func Like(l, r interface{}) Expression {
+	return &likeExpr{Left: l, Right: r}
+}
+
+// NotLike creates Expression such as "l NOT LIKE r".
+func NotLike(l, r interface{}) Expression {
+	return &likeExpr{Left: l, Right: r, Not: true}
+}
+
+// ILike creates Expression which matches case-insensitively such as "l ILIKE r".
+//
+// It is written as "l ILIKE r" in PostgreSQL, "l LIKE r" in MySQL which follows the collation,
+// and "LOWER(l) LIKE LOWER(r)" in the other dialects.
+func ILike(l, r interface{}) Expression {
+	return &likeExpr{Left: l, Right: r, CaseInsensitive: true}
+}
+
+// NotILike creates Expression such as "l NOT ILIKE r".
+func NotILike(l, r interface{}) Expression {
+	return &likeExpr{Left: l, Right: r, Not: true, CaseInsensitive: true}
+}
+
+// Contains creates Expression such as "l LIKE '%s%' ESCAPE '!'".
+// The wildcard characters in s are escaped, and s is passed as an argument.
+func Contains(l interface{}, s string) Expression {
+	return &likeExpr{Left: l, Right: "%" + EscapeLike(s) + "%", Escape: true}
+}
+
+// StartsWith creates Expression such as "l LIKE 's%' ESCAPE '!'".
+// The wildcard characters in s are escaped, and s is passed as an argument.
+func StartsWith(l interface{}, s string) Expression {
+	return &likeExpr{Left: l, Right: EscapeLike(s) + "%", Escape: true}
+}
+
+// EndsWith creates Expression such as "l LIKE '%s' ESCAPE '!'".
+// The wildcard characters in s are escaped, and s is passed as an argument.
+func EndsWith(l interface{}, s string) Expression {
+	return &likeExpr{Left: l, Right: "%" + EscapeLike(s), Escape: true}
+}
+
+type likeExpr struct {
+	Left            interface{}
+	Right           interface{}
+	Not             bool
+	CaseInsensitive bool
+	Escape          bool
+}
+
+func (e *likeExpr) String() string               { return expressionToString(e) }
+func (e *likeExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *likeExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if e.CaseInsensitive {
+		buf = ctx.Dialect.ILike(ctx, buf, e.Left, e.Right, e.Not)
+	} else {
+		buf = writeIntf(e.Left, ctx, buf)
+		if e.Not {
+			buf = append(buf, " NOT"...)
+		}
+		buf = append(buf, " LIKE "...)
+		buf = writeIntf(e.Right, ctx, buf)
+	}
+	if e.Escape {
+		buf = append(buf, " ESCAPE '"...)
+		buf = append(buf, LikeEscapeChar, '\'')
+	}
+	return buf
+}
@@ -0,0 +1,54 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestEscapeLike(t *testing.T) {
+	for i, test := range []struct{ S, V string }{
+		{S: "abc", V: "abc"},
+		{S: "100%", V: "100!%"},
+		{S: "a_b!c", V: "a!_b!!c"},
+		{S: "%_!", V: "!%!_!!"},
+	} {
+		if got := EscapeLike(test.S); got != test.V {
+			t.Errorf("tests[%d] want %s got %s", i, test.V, got)
+		}
+	}
+}
+
+var likeTests = []struct {
+	Name string
+	E    Expression
+	D    qutil.Dialect
+	SQL  string
+	Args string
+}{
+	{Name: "like", E: Like(C("name"), "a%"), D: MySQL, SQL: "`name` LIKE ?", Args: "[a%]"},
+	{Name: "not like", E: NotLike(C("name"), C("pattern")), D: PostgreSQL, SQL: `"name" NOT LIKE "pattern"`, Args: "[]"},
+	{Name: "ilike postgres", E: ILike(C("name"), "a%"), D: PostgreSQL, SQL: `"name" ILIKE $1`, Args: "[a%]"},
+	{Name: "not ilike postgres", E: NotILike(C("name"), "a%"), D: PostgreSQL, SQL: `"name" NOT ILIKE $1`, Args: "[a%]"},
+	{Name: "ilike mysql", E: ILike(C("name"), "a%"), D: MySQL, SQL: "`name` LIKE ?", Args: "[a%]"},
+	{Name: "not ilike mysql", E: NotILike(C("name"), "a%"), D: MySQL, SQL: "`name` NOT LIKE ?", Args: "[a%]"},
+	{Name: "ilike sqlite", E: ILike(C("name"), "a%"), D: SQLite, SQL: `LOWER("name") LIKE LOWER(?)`, Args: "[a%]"},
+	{Name: "not ilike sqlite", E: NotILike(C("name"), "a%"), D: SQLite, SQL: `LOWER("name") NOT LIKE LOWER(?)`, Args: "[a%]"},
+	{Name: "contains", E: Contains(C("name"), "50%_off"), D: SQLite, SQL: `"name" LIKE ? ESCAPE '!'`, Args: "[%50!%!_off%]"},
+	{Name: "starts with", E: StartsWith(C("name"), "a!"), D: PostgreSQL, SQL: `"name" LIKE $1 ESCAPE '!'`, Args: "[a!!%]"},
+	{Name: "ends with", E: EndsWith(C("name"), "_x"), D: MySQL, SQL: "`name` LIKE ? ESCAPE '!'", Args: "[%!_x]"},
+}
+
+func TestLike(t *testing.T) {
+	for i, test := range likeTests {
+		buf, ctx := qutil.NewContext(test.E, 32, 1, test.D)
+		buf = test.E.WriteExpression(ctx, buf)
+		if got := string(buf); got != test.SQL {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.SQL, got)
+		}
+		if got := fmt.Sprint(ctx.Args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+	}
+}
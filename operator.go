@@ -0,0 +1,158 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// Operator precedences which are used to decide whether the operands need parentheses.
+const (
+	// precOther is the precedence of bitwise operators and string concatenation.
+	// Their precedences differ between dialects,
+	// so the operands of them and they as operands are always enclosed in parentheses.
+	precOther = iota + 1
+	precAdditive
+	precMultiplicative
+	precUnary
+)
+
+func binaryPrecedence(op string) int {
+	switch op {
+	case "+", "-":
+		return precAdditive
+	case "*", "/", "%":
+		return precMultiplicative
+	}
+	return precOther
+}
+
+func operatorPrecedence(x interface{}) int {
+	switch v := x.(type) {
+	case *binaryOpExpr:
+		return binaryPrecedence(v.Op)
+	case *unaryOpExpr:
+		return precUnary
+	case *concatExpr:
+		return precOther
+	}
+	return 0
+}
+
+// operand returns x which is enclosed in parentheses if needed
+// as an operand of the operator whose precedence is prec.
+// right must be true if x is the right-hand side operand of the binary operator.
+func operand(x interface{}, prec int, right bool) interface{} {
+	p := operatorPrecedence(x)
+	switch {
+	case p == 0:
+		return x
+	case p == precOther, prec == precOther, prec == precUnary, p < prec, p == prec && right:
+		return &parenExpr{x.(Expression)}
+	}
+	return x
+}
+
+type parenExpr struct {
+	Expression
+}
+
+func (e *parenExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, '(')
+	buf = e.Expression.WriteExpression(ctx, buf)
+	return append(buf, ')')
+}
+
+// Add creates Expression such as "l + r".
+//
+// It can be used to increment the column such as Update(t).Set(t.C("count"), Add(t.C("count"), 1)).
+func Add(l, r interface{}) Expression { return &binaryOpExpr{Op: "+", Left: l, Right: r} }
+
+// Sub creates Expression such as "l - r".
+func Sub(l, r interface{}) Expression { return &binaryOpExpr{Op: "-", Left: l, Right: r} }
+
+// Mul creates Expression such as "l * r".
+func Mul(l, r interface{}) Expression { return &binaryOpExpr{Op: "*", Left: l, Right: r} }
+
+// Div creates Expression such as "l / r".
+func Div(l, r interface{}) Expression { return &binaryOpExpr{Op: "/", Left: l, Right: r} }
+
+// Mod creates Expression such as "l % r".
+func Mod(l, r interface{}) Expression { return &binaryOpExpr{Op: "%", Left: l, Right: r} }
+
+// BitAnd creates Expression such as "l & r".
+func BitAnd(l, r interface{}) Expression { return &binaryOpExpr{Op: "&", Left: l, Right: r} }
+
+// BitOr creates Expression such as "l | r".
+func BitOr(l, r interface{}) Expression { return &binaryOpExpr{Op: "|", Left: l, Right: r} }
+
+// BitXor creates Expression such as "l # r".
+//
+// It is written as "l # r" in PostgreSQL, "l ^ r" in MySQL,
+// and "(l | r) - (l & r)" in SQLite which has no XOR operator.
+func BitXor(l, r interface{}) Expression { return &binaryOpExpr{Op: "XOR", Left: l, Right: r} }
+
+// ShiftLeft creates Expression such as "l << r".
+func ShiftLeft(l, r interface{}) Expression { return &binaryOpExpr{Op: "<<", Left: l, Right: r} }
+
+// ShiftRight creates Expression such as "l >> r".
+func ShiftRight(l, r interface{}) Expression { return &binaryOpExpr{Op: ">>", Left: l, Right: r} }
+
+// Neg creates Expression such as "-v".
+func Neg(v interface{}) Expression { return &unaryOpExpr{Op: "-", V: v} }
+
+// BitNot creates Expression such as "~v".
+func BitNot(v interface{}) Expression { return &unaryOpExpr{Op: "~", V: v} }
+
+type binaryOpExpr struct {
+	Op    string
+	Left  interface{}
+	Right interface{}
+}
+
+func (e *binaryOpExpr) String() string               { return expressionToString(e) }
+func (e *binaryOpExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *binaryOpExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	p := binaryPrecedence(e.Op)
+	l, r := operand(e.Left, p, false), operand(e.Right, p, true)
+	if e.Op == "XOR" {
+		return ctx.Dialect.BitXor(ctx, buf, l, r)
+	}
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, ' ')
+	buf = append(buf, e.Op...)
+	buf = append(buf, ' ')
+	return writeIntf(r, ctx, buf)
+}
+
+type unaryOpExpr struct {
+	Op string
+	V  interface{}
+}
+
+func (e *unaryOpExpr) String() string               { return expressionToString(e) }
+func (e *unaryOpExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *unaryOpExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, e.Op...)
+	return writeIntf(operand(e.V, precUnary, true), ctx, buf)
+}
+
+// Concat creates Expression which concatenates strings such as "v[0] || v[1]".
+//
+// It is written as "CONCAT(v[0], v[1])" in MySQL, because "||" means OR in MySQL by default.
+func Concat(v ...interface{}) Expression {
+	if len(v) == 0 {
+		panic("q: need at least one argument to concatenate.")
+	}
+	return &concatExpr{Values: v}
+}
+
+type concatExpr struct {
+	Values []interface{}
+}
+
+func (e *concatExpr) String() string               { return expressionToString(e) }
+func (e *concatExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *concatExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	vs := make([]interface{}, len(e.Values))
+	for i, v := range e.Values {
+		vs[i] = operand(v, precOther, i > 0)
+	}
+	return ctx.Dialect.Concat(ctx, buf, vs)
+}
@@ -0,0 +1,69 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+var operatorTests = []struct {
+	Name string
+	E    Expression
+	D    qutil.Dialect
+	SQL  string
+	Args string
+}{
+	{Name: "add", E: Add(C("a"), 1), D: MySQL, SQL: "`a` + ?", Args: "[1]"},
+	{Name: "left associative", E: Sub(Sub(C("a"), C("b")), C("c")), D: SQLite, SQL: `"a" - "b" - "c"`, Args: "[]"},
+	{Name: "right operand", E: Sub(C("a"), Sub(C("b"), C("c"))), D: SQLite, SQL: `"a" - ("b" - "c")`, Args: "[]"},
+	{Name: "precedence", E: Mul(Add(C("a"), 1), Div(C("b"), 2)), D: PostgreSQL, SQL: `("a" + $1) * ("b" / $2)`, Args: "[1 2]"},
+	{Name: "no parentheses", E: Add(Mul(C("a"), 2), Mod(C("b"), 3)), D: PostgreSQL, SQL: `"a" * $1 + "b" % $2`, Args: "[2 3]"},
+	{Name: "neg", E: Neg(C("a")), D: MySQL, SQL: "-`a`", Args: "[]"},
+	{Name: "double neg", E: Neg(Neg(C("a"))), D: MySQL, SQL: "-(-`a`)", Args: "[]"},
+	{Name: "neg operand", E: Add(Neg(Add(C("a"), C("b"))), Neg(C("c"))), D: MySQL, SQL: "-(`a` + `b`) + -`c`", Args: "[]"},
+	{Name: "bitwise", E: BitOr(BitAnd(C("a"), 1), ShiftLeft(C("b"), 2)), D: MySQL, SQL: "(`a` & ?) | (`b` << ?)", Args: "[1 2]"},
+	{Name: "bitwise in arithmetic", E: Add(ShiftRight(C("a"), 1), BitNot(C("b"))), D: SQLite, SQL: `("a" >> ?) + ~"b"`, Args: "[1]"},
+	{Name: "arithmetic in bitwise", E: BitAnd(Add(C("a"), 1), C("b")), D: SQLite, SQL: `("a" + ?) & "b"`, Args: "[1]"},
+	{Name: "xor mysql", E: BitXor(C("a"), Add(C("b"), 1)), D: MySQL, SQL: "`a` ^ (`b` + ?)", Args: "[1]"},
+	{Name: "xor postgresql", E: BitXor(C("a"), 1), D: PostgreSQL, SQL: `"a" # $1`, Args: "[1]"},
+	{Name: "xor sqlite", E: BitXor(C("a"), 1), D: SQLite, SQL: `("a" | ?) - ("a" & ?)`, Args: "[1 1]"},
+	{Name: "concat mysql", E: Concat(C("first"), " ", C("last")), D: MySQL, SQL: "CONCAT(`first`, ?, `last`)", Args: "[ ]"},
+	{Name: "concat postgresql", E: Concat(C("first"), " ", C("last")), D: PostgreSQL, SQL: `"first" || $1 || "last"`, Args: "[ ]"},
+	{Name: "concat sqlite", E: Concat(C("name"), Add(C("id"), 1)), D: SQLite, SQL: `"name" || ("id" + ?)`, Args: "[1]"},
+	{Name: "concat in arithmetic", E: Add(Concat(C("a"), C("b")), 1), D: SQLite, SQL: `("a" || "b") + ?`, Args: "[1]"},
+	{Name: "comparison", E: Gt(Add(C("a"), C("b")), Mul(C("c"), 2)), D: PostgreSQL, SQL: `"a" + "b" > "c" * $1`, Args: "[2]"},
+}
+
+func TestOperator(t *testing.T) {
+	for i, test := range operatorTests {
+		buf, ctx := qutil.NewContext(test.E, 32, 1, test.D)
+		buf = test.E.WriteExpression(ctx, buf)
+		if got := string(buf); got != test.SQL {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.SQL, got)
+		}
+		if got := fmt.Sprint(ctx.Args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+	}
+}
+
+func TestOperatorUpdate(t *testing.T) {
+	user := T("user")
+	sql, args := Update(user).Set(user.C("count"), Add(user.C("count"), 1)).Where(Eq(user.C("id"), 1)).SetDialect(PostgreSQL).ToSQL()
+	if want := `UPDATE "user" SET "count" = "count" + $1 WHERE "id" = $2`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if want, got := "[1 1]", fmt.Sprint(args); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestConcatPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	Concat()
+}
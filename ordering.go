@@ -0,0 +1,103 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// NullOrder represents where NULLs are sorted in ORDER BY clause.
+type NullOrder int
+
+const (
+	// NullsDefault sorts NULLs in the default position of the dialect.
+	NullsDefault NullOrder = iota
+	// NullsFirst sorts NULLs before the other values.
+	NullsFirst
+	// NullsLast sorts NULLs after the other values.
+	NullsLast
+)
+
+// Ordering represents an element of ORDER BY clause such as "x COLLATE "C" DESC NULLS LAST".
+type Ordering struct {
+	Expression
+	Ascending bool
+	Nulls     NullOrder
+	Collation string
+}
+
+// Asc creates Ordering such as "e ASC".
+func Asc(e Expression) Ordering {
+	return Ordering{Expression: e, Ascending: true}
+}
+
+// Desc creates Ordering such as "e DESC".
+func Desc(e Expression) Ordering {
+	return Ordering{Expression: e}
+}
+
+// NullsFirst returns the copy of o which sorts NULLs before the other values.
+func (o Ordering) NullsFirst() Ordering {
+	o.Nulls = NullsFirst
+	return o
+}
+
+// NullsLast returns the copy of o which sorts NULLs after the other values.
+func (o Ordering) NullsLast() Ordering {
+	o.Nulls = NullsLast
+	return o
+}
+
+// Collate returns the copy of o which compares the values by the collation such as "e COLLATE "name"".
+func (o Ordering) Collate(name string) Ordering {
+	o.Collation = name
+	return o
+}
+
+// nullsFirst reports whether NULLs are sorted before the other values in the dialect.
+func (o Ordering) nullsFirst(d qutil.Dialect) bool {
+	switch o.Nulls {
+	case NullsFirst:
+		return true
+	case NullsLast:
+		return false
+	}
+	return o.Ascending == d.NullIsSmallest()
+}
+
+// writeOrders writes the elements of ORDER BY clause.
+// In the dialect which doesn't support NULLS FIRST/LAST such as MySQL,
+// the placement of NULLs is emulated by the sort key such as "x IS NULL DESC, x ASC"
+// if it differs from the default.
+func writeOrders(ctx *qutil.Context, buf []byte, orders []Ordering) []byte {
+	for i, o := range orders {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		if o.Nulls != NullsDefault && !ctx.Dialect.CanUseNullsOrder() {
+			first := o.nullsFirst(ctx.Dialect)
+			if first != (o.Ascending == ctx.Dialect.NullIsSmallest()) {
+				buf = o.Expression.WriteExpression(ctx, buf)
+				if first {
+					buf = append(buf, " IS NULL DESC, "...)
+				} else {
+					buf = append(buf, " IS NULL ASC, "...)
+				}
+			}
+		}
+		buf = o.Expression.WriteExpression(ctx, buf)
+		if o.Collation != "" {
+			buf = append(buf, " COLLATE "...)
+			buf = ctx.Dialect.Quote(buf, o.Collation)
+		}
+		if o.Ascending {
+			buf = append(buf, " ASC"...)
+		} else {
+			buf = append(buf, " DESC"...)
+		}
+		if o.Nulls != NullsDefault && ctx.Dialect.CanUseNullsOrder() {
+			if o.Nulls == NullsFirst {
+				buf = append(buf, " NULLS FIRST"...)
+			} else {
+				buf = append(buf, " NULLS LAST"...)
+			}
+		}
+	}
+	return buf
+}
@@ -0,0 +1,110 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestOrdering(t *testing.T) {
+	for i, test := range []struct {
+		B    *ZSelectBuilder
+		D    qutil.Dialect
+		SQL  string
+		Args string
+	}{
+		{
+			B:    Select().From(T("user")).Order(Desc(C("score")).NullsLast(), Asc(C("name")).Collate("C")),
+			D:    PostgreSQL,
+			SQL:  `SELECT * FROM "user" ORDER BY "score" DESC NULLS LAST, "name" COLLATE "C" ASC`,
+			Args: "[]",
+		},
+		{
+			B:    Select().From(T("user")).Order(Desc(C("score")).NullsLast(), Asc(C("name")).NullsFirst()),
+			D:    SQLite,
+			SQL:  `SELECT * FROM "user" ORDER BY "score" DESC NULLS LAST, "name" ASC NULLS FIRST`,
+			Args: "[]",
+		},
+		{
+			B:    Select().From(T("user")).Order(Desc(C("score")).NullsLast(), Asc(C("name")).Collate("utf8mb4_bin").NullsLast()),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `user` ORDER BY `score` DESC, `name` IS NULL ASC, `name` COLLATE `utf8mb4_bin` ASC",
+			Args: "[]",
+		},
+		{
+			B:    Select().From(T("user")).Order(Desc(C("score")).NullsFirst(), Asc(C("name")).NullsFirst()),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `user` ORDER BY `score` IS NULL DESC, `score` DESC, `name` ASC",
+			Args: "[]",
+		},
+		{
+			B:    Select().From(T("user")).Order(Asc(Add(C("score"), 1).C()).NullsLast()),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `user` ORDER BY `score` + ? IS NULL ASC, `score` + ? ASC",
+			Args: "[1 1]",
+		},
+		{
+			B:    Select().From(T("user")).OrderBy(C("id"), false),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `user` ORDER BY `id` DESC",
+			Args: "[]",
+		},
+	} {
+		sql, args := test.B.SetDialect(test.D).ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] want %s got %s", i, test.Args, got)
+		}
+		if got := test.B.Clone().String(); got != test.B.String() {
+			t.Errorf("tests[%d] clone want %s got %s", i, test.B.String(), got)
+		}
+	}
+}
+
+func TestOrderingSeek(t *testing.T) {
+	for i, test := range []struct {
+		B    *ZSelectBuilder
+		D    qutil.Dialect
+		SQL  string
+		Args string
+	}{
+		{
+			B:    Select().From(T("post")).Order(Asc(C("rank")).NullsLast(), Asc(C("id"))).Seek([]interface{}{3, 10}, 20),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `post` WHERE ((`rank` > ?)OR(`rank` IS NULL))OR((`rank` <=> ?)AND(`id` > ?)) ORDER BY `rank` IS NULL ASC, `rank` ASC, `id` ASC LIMIT ?",
+			Args: "[3 3 10 21]",
+		},
+		{
+			B:    Select().From(T("post")).Order(Asc(C("rank")).NullsLast(), Asc(C("id"))).Seek([]interface{}{nil, 10}, 20),
+			D:    PostgreSQL,
+			SQL:  `SELECT * FROM "post" WHERE ("rank" IS NULL)AND("id" > $1) ORDER BY "rank" ASC NULLS LAST, "id" ASC LIMIT $2`,
+			Args: "[10 21]",
+		},
+		{
+			B:    Select().From(T("post")).Order(Desc(C("rank")).NullsFirst(), Desc(C("id"))).Seek([]interface{}{nil, 10}, 20),
+			D:    MySQL,
+			SQL:  "SELECT * FROM `post` WHERE (`rank` IS NOT NULL)OR((`rank` IS NULL)AND(`id` < ?)) ORDER BY `rank` IS NULL DESC, `rank` DESC, `id` DESC LIMIT ?",
+			Args: "[10 21]",
+		},
+	} {
+		sql, args := test.B.SetDialect(test.D).ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] want %s got %s", i, test.Args, got)
+		}
+	}
+}
+
+func TestOrderingDistinctOn(t *testing.T) {
+	b := Select().DistinctOn(C("user_id")).Column(C("user_id"), C("rank")).From(T("post")).
+		Order(Asc(C("user_id")), Desc(C("rank")).NullsLast()).SetDialect(SQLite)
+	want := `SELECT "user_id", "rank" FROM (SELECT "user_id", "rank", ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "user_id" ASC, "rank" DESC NULLS LAST) AS "q_rn" FROM "post") AS "q_t" WHERE "q_rn" = 1 ORDER BY "user_id" ASC, "rank" DESC NULLS LAST`
+	if got, _ := b.ToSQL(); got != want {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
@@ -0,0 +1,322 @@
+package q
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType represents the type of the values of Field.
+type FieldType int
+
+const (
+	// StringField accepts any string.
+	StringField FieldType = iota
+	// IntField accepts the integers such as "-12".
+	IntField
+	// FloatField accepts the numbers such as "1.5".
+	FloatField
+	// BoolField accepts the boolean values such as "true" and "0".
+	BoolField
+	// TimeField accepts the time in RFC 3339 format such as "2006-01-02T15:04:05Z" or the date such as "2006-01-02".
+	TimeField
+)
+
+// Field represents the field which can be used in the sort and filter parameters.
+type Field struct {
+	Column   Column
+	Type     FieldType
+	NoSort   bool     // Whether the field can't be used in the sort parameter.
+	NoFilter bool     // Whether the field can't be used in the filter parameter.
+	Ops      []string // The operators which can be used in the filter parameter, such as "eq". If nil, all operators for Type can be used.
+}
+
+// Fields is the whitelist of the fields which can be used in the sort and filter parameters.
+// The keys are the names which users give.
+type Fields map[string]Field
+
+// The limits of ParseSort and ParseFilter.
+// The parameters come from users, so the long lists are rejected to keep the SQL and its arguments small.
+var (
+	// MaxParamTerms is the maximum number of the terms in a parameter.
+	MaxParamTerms = 32
+	// MaxParamValues is the maximum number of the values of "in" and "nin" operators.
+	MaxParamValues = 100
+)
+
+// ParamErrorKind represents the kind of ParamError.
+type ParamErrorKind int
+
+const (
+	// ParamSyntax means the term can't be parsed.
+	ParamSyntax ParamErrorKind = iota + 1
+	// ParamUnknownField means the field is not in Fields or is not allowed in the parameter.
+	ParamUnknownField
+	// ParamUnknownOperator means the operator is unknown or is not allowed for the field.
+	ParamUnknownOperator
+	// ParamInvalidValue means the value can't be converted into the type of the field.
+	ParamInvalidValue
+	// ParamTooMany means the parameter has more terms than MaxParamTerms,
+	// or the term has more values than MaxParamValues.
+	ParamTooMany
+)
+
+// ParamError represents an error which is occurred in ParseSort and ParseFilter.
+// The message doesn't contain any internal names such as the column names, so it can be shown to users.
+type ParamError struct {
+	Kind  ParamErrorKind
+	Param string // "sort" or "filter".
+	Term  string // The term which has the error, such as "age>=x".
+	Field string // The field name in the term, or "" if the term can't be parsed.
+	Msg   string
+}
+
+// Error implements error interface.
+func (e *ParamError) Error() string {
+	return "q: invalid " + e.Param + " " + strconv.Quote(e.Term) + ": " + e.Msg
+}
+
+// ParseSort parses the sort parameter such as "-created_at,name" into the orderings,
+// which can be passed to ZSelectBuilder.Order.
+// The terms are separated by ",", and each term is the field name with an optional prefix;
+// "-" means the descending order and "+" means the ascending order which is the default.
+// The number of the terms is limited by MaxParamTerms.
+// If s is empty, ParseSort returns nil.
+func ParseSort(s string, fields Fields) ([]Ordering, error) {
+	if s == "" {
+		return nil, nil
+	}
+	terms, err := splitTerms(s, "sort")
+	if err != nil {
+		return nil, err
+	}
+	r := make([]Ordering, 0, len(terms))
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		name, asc := term, true
+		if strings.HasPrefix(name, "-") {
+			name, asc = name[1:], false
+		} else if strings.HasPrefix(name, "+") {
+			name = name[1:]
+		}
+		if !isFieldName(name) {
+			return nil, &ParamError{ParamSyntax, "sort", term, "", "expected field name"}
+		}
+		f, ok := fields[name]
+		if !ok || f.NoSort {
+			return nil, &ParamError{ParamUnknownField, "sort", term, name, "unknown field " + strconv.Quote(name)}
+		}
+		if seen[name] {
+			return nil, &ParamError{ParamSyntax, "sort", term, name, "duplicate field " + strconv.Quote(name)}
+		}
+		seen[name] = true
+		r = append(r, Ordering{Expression: f.Column, Ascending: asc})
+	}
+	return r, nil
+}
+
+// splitTerms splits s by ",", and returns an error if s has more terms than MaxParamTerms.
+func splitTerms(s, param string) ([]string, error) {
+	terms := strings.SplitN(s, ",", MaxParamTerms+1)
+	if len(terms) > MaxParamTerms {
+		term := strings.SplitN(terms[MaxParamTerms], ",", 2)[0]
+		return nil, &ParamError{ParamTooMany, param, term, "", "too many terms, the maximum is " + strconv.Itoa(MaxParamTerms)}
+	}
+	return terms, nil
+}
+
+// filterOps maps the symbols to the operator names.
+var filterOps = []struct {
+	Symbol, Name string
+}{
+	{">=", "gte"}, {"<=", "lte"}, {"!=", "ne"}, {"=", "eq"}, {">", "gt"}, {"<", "lt"},
+}
+
+// ParseFilter parses the filter parameter such as "age>=18,status:in:a|b" into the conditions,
+// which can be passed to ZSelectBuilder.Where.
+//
+// The terms are separated by ",", and each term is "name<op>value" or "name:op:value".
+// The operators are:
+//
+//	=, eq          "x = value"
+//	!=, ne         "x != value"
+//	>, gt          "x > value"
+//	>=, gte        "x >= value"
+//	<, lt          "x < value"
+//	<=, lte        "x <= value"
+//	in             "x IN (values)", the values are separated by "|"
+//	nin            "x NOT IN (values)", the values are separated by "|"
+//	contains       "x LIKE '%value%'", for StringField only
+//	startswith     "x LIKE 'value%'", for StringField only
+//	endswith       "x LIKE '%value'", for StringField only
+//	null           "x IS NULL" if value is true, otherwise "x IS NOT NULL"
+//
+// The values are converted into the type of the field and are passed as the arguments,
+// so the values never become a part of SQL. The values can't contain ",".
+// The number of the terms and the values of "in" and "nin" are limited by MaxParamTerms and MaxParamValues.
+// If s is empty, ParseFilter returns nil.
+func ParseFilter(s string, fields Fields) ([]Expression, error) {
+	if s == "" {
+		return nil, nil
+	}
+	terms, err := splitTerms(s, "filter")
+	if err != nil {
+		return nil, err
+	}
+	r := make([]Expression, 0, len(terms))
+	for _, term := range terms {
+		e, err := parseFilterTerm(term, fields)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, e)
+	}
+	return r, nil
+}
+
+func parseFilterTerm(term string, fields Fields) (Expression, error) {
+	i := 0
+	for i < len(term) && isFieldNameChar(term[i]) {
+		i++
+	}
+	name, rest := term[:i], term[i:]
+	if !isFieldName(name) {
+		return nil, &ParamError{ParamSyntax, "filter", term, "", "expected field name"}
+	}
+
+	var op, value string
+	if strings.HasPrefix(rest, ":") {
+		j := strings.IndexByte(rest[1:], ':')
+		if j == -1 {
+			return nil, &ParamError{ParamSyntax, "filter", term, name, `expected ":" after operator`}
+		}
+		op, value = rest[1:j+1], rest[j+2:]
+	} else {
+		for _, o := range filterOps {
+			if strings.HasPrefix(rest, o.Symbol) {
+				op, value = o.Name, rest[len(o.Symbol):]
+				break
+			}
+		}
+		if op == "" {
+			return nil, &ParamError{ParamSyntax, "filter", term, name, "expected operator"}
+		}
+	}
+
+	f, ok := fields[name]
+	if !ok || f.NoFilter {
+		return nil, &ParamError{ParamUnknownField, "filter", term, name, "unknown field " + strconv.Quote(name)}
+	}
+	if !f.allows(op) {
+		return nil, &ParamError{ParamUnknownOperator, "filter", term, name, "operator " + strconv.Quote(op) + " is not allowed"}
+	}
+
+	switch op {
+	case "in", "nin":
+		vs := strings.SplitN(value, "|", MaxParamValues+1)
+		if len(vs) > MaxParamValues {
+			return nil, &ParamError{ParamTooMany, "filter", term, name, "too many values, the maximum is " + strconv.Itoa(MaxParamValues)}
+		}
+		args := make([]interface{}, len(vs))
+		for i, v := range vs {
+			x, err := f.convert(v)
+			if err != nil {
+				return nil, &ParamError{ParamInvalidValue, "filter", term, name, err.Error()}
+			}
+			args[i] = x
+		}
+		return filterOp(f.Column, op, args), nil
+	case "contains", "startswith", "endswith":
+		return filterOp(f.Column, op, value), nil
+	case "null":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &ParamError{ParamInvalidValue, "filter", term, name, strconv.Quote(value) + " is not a boolean"}
+		}
+		if b {
+			return Eq(f.Column, nil), nil
+		}
+		return Neq(f.Column, nil), nil
+	}
+
+	x, err := f.convert(value)
+	if err != nil {
+		return nil, &ParamError{ParamInvalidValue, "filter", term, name, err.Error()}
+	}
+	return filterOp(f.Column, op, x), nil
+}
+
+// allows reports whether op can be used with f.
+func (f Field) allows(op string) bool {
+	switch op {
+	case "eq", "ne", "in", "nin", "null":
+	case "gt", "gte", "lt", "lte":
+		if f.Type == BoolField {
+			return false
+		}
+	case "contains", "startswith", "endswith":
+		if f.Type != StringField {
+			return false
+		}
+	default:
+		return false
+	}
+	if f.Ops == nil {
+		return true
+	}
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// convert converts v into the type of f.
+func (f Field) convert(v string) (interface{}, error) {
+	switch f.Type {
+	case IntField:
+		if x, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return x, nil
+		}
+		return nil, errors.New(strconv.Quote(v) + " is not an integer")
+	case FloatField:
+		// NaN and Inf are rejected because they can't be stored in most databases.
+		if x, err := strconv.ParseFloat(v, 64); err == nil && !math.IsNaN(x) && !math.IsInf(x, 0) {
+			return x, nil
+		}
+		return nil, errors.New(strconv.Quote(v) + " is not a number")
+	case BoolField:
+		if x, err := strconv.ParseBool(v); err == nil {
+			return x, nil
+		}
+		return nil, errors.New(strconv.Quote(v) + " is not a boolean")
+	case TimeField:
+		if x, err := time.Parse(time.RFC3339, v); err == nil {
+			return x, nil
+		}
+		if x, err := time.Parse("2006-01-02", v); err == nil {
+			return x, nil
+		}
+		return nil, errors.New(strconv.Quote(v) + " is not a time")
+	}
+	return v, nil
+}
+
+func isFieldNameChar(c byte) bool {
+	return c == '_' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isFieldNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package q
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var paramFields = Fields{
+	"name":       {Column: C("name")},
+	"age":        {Column: C("age"), Type: IntField},
+	"score":      {Column: C("score"), Type: FloatField, NoSort: true},
+	"active":     {Column: C("active"), Type: BoolField},
+	"created_at": {Column: C("created_at"), Type: TimeField},
+	"status":     {Column: C("status"), Ops: []string{"eq", "in"}},
+	"secret":     {Column: C("secret"), NoFilter: true},
+}
+
+func TestParseSort(t *testing.T) {
+	for i, test := range []struct {
+		S   string
+		SQL string
+	}{
+		{"", `SELECT * FROM "user"`},
+		{"-created_at,name", `SELECT * FROM "user" ORDER BY "created_at" DESC, "name" ASC`},
+		{"+age,secret", `SELECT * FROM "user" ORDER BY "age" ASC, "secret" ASC`},
+	} {
+		orders, err := ParseSort(test.S, paramFields)
+		if err != nil {
+			t.Errorf("tests[%d] unexpected error %v", i, err)
+			continue
+		}
+		if got, _ := Select().From(T("user")).Order(orders...).ToSQL(); got != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, got)
+		}
+	}
+}
+
+func TestParseSortError(t *testing.T) {
+	for i, test := range []struct {
+		S     string
+		Kind  ParamErrorKind
+		Field string
+		Err   string
+	}{
+		{"name,", ParamSyntax, "", `q: invalid sort "": expected field name`},
+		{"-na;me", ParamSyntax, "", `q: invalid sort "-na;me": expected field name`},
+		{"password", ParamUnknownField, "password", `q: invalid sort "password": unknown field "password"`},
+		{"-score", ParamUnknownField, "score", `q: invalid sort "-score": unknown field "score"`},
+		{"name,-name", ParamSyntax, "name", `q: invalid sort "-name": duplicate field "name"`},
+		{strings.Repeat("name,", MaxParamTerms) + "age", ParamTooMany, "", `q: invalid sort "age": too many terms, the maximum is 32`},
+	} {
+		_, err := ParseSort(test.S, paramFields)
+		pe, ok := err.(*ParamError)
+		if !ok {
+			t.Errorf("tests[%d] want *ParamError got %#v", i, err)
+			continue
+		}
+		if pe.Kind != test.Kind || pe.Param != "sort" || pe.Field != test.Field || pe.Error() != test.Err {
+			t.Errorf("tests[%d] unexpected error %#v %s", i, pe, pe.Error())
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	for i, test := range []struct {
+		S    string
+		SQL  string
+		Args string
+	}{
+		{"", `SELECT * FROM "user"`, "[]"},
+		{"age>=18,status:in:a|b", `SELECT * FROM "user" WHERE ("age" >= ?)AND("status" IN (?,?))`, "[18 a b]"},
+		{"name=x=y,score<1.5,active!=false", `SELECT * FROM "user" WHERE ("name" = ?)AND("score" < ?)AND("active" != ?)`, "[x=y 1.5 false]"},
+		{"age:nin:1|2,age:gt:3,age<=4,age>5", `SELECT * FROM "user" WHERE ("age" NOT IN (?,?))AND("age" > ?)AND("age" <= ?)AND("age" > ?)`, "[1 2 3 4 5]"},
+		{"name:contains:a%b,name:startswith:c,name:endswith:d", `SELECT * FROM "user" WHERE ("name" LIKE ? ESCAPE '!')AND("name" LIKE ? ESCAPE '!')AND("name" LIKE ? ESCAPE '!')`, "[%a!%b% c% %d]"},
+		{"created_at:null:true,name:null:0", `SELECT * FROM "user" WHERE ("created_at" IS NULL)AND("name" IS NOT NULL)`, "[]"},
+		{strings.Repeat("age=1,", MaxParamTerms-1) + "age=2", `SELECT * FROM "user" WHERE ` + strings.Repeat(`("age" = ?)AND`, MaxParamTerms-1) + `("age" = ?)`, "[" + strings.Repeat("1 ", MaxParamTerms-1) + "2]"},
+		{"created_at:lt:2020-01-02", `SELECT * FROM "user" WHERE "created_at" < ?`, "[" + time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC).String() + "]"},
+	} {
+		conds, err := ParseFilter(test.S, paramFields)
+		if err != nil {
+			t.Errorf("tests[%d] unexpected error %v", i, err)
+			continue
+		}
+		sql, args := Select().From(T("user")).Where(conds...).ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] want %s got %s", i, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] want %s got %s", i, test.Args, got)
+		}
+	}
+}
+
+func TestParseFilterError(t *testing.T) {
+	for i, test := range []struct {
+		S     string
+		Kind  ParamErrorKind
+		Field string
+		Err   string
+	}{
+		{"=1", ParamSyntax, "", `q: invalid filter "=1": expected field name`},
+		{"age", ParamSyntax, "age", `q: invalid filter "age": expected operator`},
+		{"age:gt", ParamSyntax, "age", `q: invalid filter "age:gt": expected ":" after operator`},
+		{"password=x", ParamUnknownField, "password", `q: invalid filter "password=x": unknown field "password"`},
+		{"secret=x", ParamUnknownField, "secret", `q: invalid filter "secret=x": unknown field "secret"`},
+		{"age:like:1", ParamUnknownOperator, "age", `q: invalid filter "age:like:1": operator "like" is not allowed`},
+		{"age:contains:1", ParamUnknownOperator, "age", `q: invalid filter "age:contains:1": operator "contains" is not allowed`},
+		{"active>true", ParamUnknownOperator, "active", `q: invalid filter "active>true": operator "gt" is not allowed`},
+		{"status!=a", ParamUnknownOperator, "status", `q: invalid filter "status!=a": operator "ne" is not allowed`},
+		{"age>=x", ParamInvalidValue, "age", `q: invalid filter "age>=x": "x" is not an integer`},
+		{"age:in:1|x", ParamInvalidValue, "age", `q: invalid filter "age:in:1|x": "x" is not an integer`},
+		{"score=a", ParamInvalidValue, "score", `q: invalid filter "score=a": "a" is not a number`},
+		{"score=NaN", ParamInvalidValue, "score", `q: invalid filter "score=NaN": "NaN" is not a number`},
+		{"score<-Inf", ParamInvalidValue, "score", `q: invalid filter "score<-Inf": "-Inf" is not a number`},
+		{"score>1e400", ParamInvalidValue, "score", `q: invalid filter "score>1e400": "1e400" is not a number`},
+		{strings.Repeat("age=1,", MaxParamTerms) + "age=2", ParamTooMany, "", `q: invalid filter "age=2": too many terms, the maximum is 32`},
+		{"age:in:" + strings.Repeat("1|", MaxParamValues) + "2", ParamTooMany, "age", `q: invalid filter "age:in:` + strings.Repeat("1|", MaxParamValues) + `2": too many values, the maximum is 100`},
+		{"active=yes", ParamInvalidValue, "active", `q: invalid filter "active=yes": "yes" is not a boolean`},
+		{"created_at>2020", ParamInvalidValue, "created_at", `q: invalid filter "created_at>2020": "2020" is not a time`},
+		{"name:null:x", ParamInvalidValue, "name", `q: invalid filter "name:null:x": "x" is not a boolean`},
+	} {
+		_, err := ParseFilter(test.S, paramFields)
+		pe, ok := err.(*ParamError)
+		if !ok {
+			t.Errorf("tests[%d] want *ParamError got %#v", i, err)
+			continue
+		}
+		if pe.Kind != test.Kind || pe.Param != "filter" || pe.Field != test.Field || pe.Error() != test.Err {
+			t.Errorf("tests[%d] unexpected error %#v %s", i, pe, pe.Error())
+		}
+	}
+}
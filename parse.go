@@ -0,0 +1,998 @@
+package q
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/oov/q/qutil"
+)
+
+// ParseError represents an error which is occurred in Parse.
+type ParseError struct {
+	Pos int // Byte offset in the SQL.
+	Msg string
+}
+
+// Error implements error interface.
+func (e *ParseError) Error() string {
+	return "q: parse error at " + strconv.Itoa(e.Pos) + ": " + e.Msg
+}
+
+type tokenKind int
+
+const (
+	tkEOF tokenKind = iota
+	tkIdent
+	tkQuotedIdent
+	tkString
+	tkNumber
+	tkPlaceholder
+	tkOp
+)
+
+type token struct {
+	Kind tokenKind
+	Text string
+	Pos  int
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// stringMode represents how the string literals are written in the SQL.
+type stringMode int
+
+const (
+	// strictStrings rejects the backslashes in the string literals because the meaning depends on the dialect.
+	strictStrings stringMode = iota
+	// standardStrings treats the backslashes as the ordinary characters.
+	standardStrings
+	// mySQLStrings accepts the backslash escapes and the double-quoted string literals.
+	// In this mode, "||" also means OR instead of the concatenation as MySQL does by default.
+	mySQLStrings
+)
+
+// mySQLEscapes maps the escape sequences in MySQL to the characters.
+// The other escaped characters are the characters themselves, except "\%" and "\_".
+var mySQLEscapes = map[byte]byte{'0': 0, 'b': '\b', 'n': '\n', 'r': '\r', 't': '\t', 'Z': 0x1a}
+
+func tokenize(s string, mode stringMode) ([]token, error) {
+	var r []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case isIdentStart(c):
+			st := i
+			for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i]) || s[i] == '$') {
+				i++
+			}
+			r = append(r, token{tkIdent, s[st:i], st})
+		case isDigit(c) || c == '.' && i+1 < len(s) && isDigit(s[i+1]):
+			st := i
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+				i++
+				if i < len(s) && (s[i] == '+' || s[i] == '-') {
+					i++
+				}
+				for i < len(s) && isDigit(s[i]) {
+					i++
+				}
+			}
+			r = append(r, token{tkNumber, s[st:i], st})
+		case c == '\'' || c == '"' || c == '`':
+			str := c == '\'' || c == '"' && mode == mySQLStrings
+			st := i
+			var b []byte
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, &ParseError{st, "unterminated quoted string"}
+				}
+				if s[i] == '\\' && str && mode != standardStrings {
+					if mode == strictStrings {
+						return nil, &ParseError{i, "backslash in string literal is ambiguous, use ParseDialect"}
+					}
+					if i+1 >= len(s) {
+						return nil, &ParseError{st, "unterminated quoted string"}
+					}
+					i++
+					if e, ok := mySQLEscapes[s[i]]; ok {
+						b = append(b, e)
+					} else if s[i] == '%' || s[i] == '_' {
+						b = append(b, '\\', s[i])
+					} else {
+						b = append(b, s[i])
+					}
+					continue
+				}
+				if s[i] == c {
+					if i+1 < len(s) && s[i+1] == c {
+						b = append(b, c)
+						i++
+						continue
+					}
+					break
+				}
+				b = append(b, s[i])
+			}
+			i++
+			if str {
+				r = append(r, token{tkString, string(b), st})
+			} else {
+				r = append(r, token{tkQuotedIdent, string(b), st})
+			}
+		case c == '?':
+			r = append(r, token{tkPlaceholder, "?", i})
+			i++
+		case c == '$' && i+1 < len(s) && isDigit(s[i+1]):
+			st := i
+			for i++; i < len(s) && isDigit(s[i]); i++ {
+			}
+			r = append(r, token{tkPlaceholder, s[st:i], st})
+		default:
+			st := i
+			if i+1 < len(s) {
+				switch s[i : i+2] {
+				case "<=", ">=", "<>", "!=", "||":
+					r = append(r, token{tkOp, s[i : i+2], st})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>(),.*+-/%;", rune(c)) {
+				return nil, &ParseError{st, "unexpected character " + strconv.Quote(string(c))}
+			}
+			r = append(r, token{tkOp, s[i : i+1], st})
+			i++
+		}
+	}
+	return append(r, token{tkEOF, "", len(s)}), nil
+}
+
+var reservedWords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "FROM": true, "WHERE": true, "GROUP": true, "BY": true,
+	"HAVING": true, "ORDER": true, "LIMIT": true, "OFFSET": true, "AS": true, "ON": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "OUTER": true, "CROSS": true,
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true, "LIKE": true, "ILIKE": true,
+	"BETWEEN": true, "ASC": true, "DESC": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "UNION": true, "INSERT": true, "INTO": true, "VALUES": true,
+	"UPDATE": true, "SET": true, "DELETE": true, "RETURNING": true, "EXISTS": true, "COLLATE": true,
+	"ANY": true, "SOME": true, "ALL": true,
+}
+
+type parser struct {
+	toks   []token
+	pos    int
+	mode   stringMode
+	args   []interface{}
+	argis  map[int]int // The indices of the arguments for "?" by the positions in the SQL.
+	used   []bool
+	scopes []map[string]Table
+}
+
+func (p *parser) fail(pos int, format string, a ...interface{}) {
+	panic(&ParseError{pos, fmt.Sprintf(format, a...)})
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.Kind != tkEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether the token at offset n is the keyword kw.
+func (p *parser) isKeyword(n int, kw string) bool {
+	if p.pos+n >= len(p.toks) {
+		return false
+	}
+	t := p.toks[p.pos+n]
+	return t.Kind == tkIdent && strings.EqualFold(t.Text, kw)
+}
+
+func (p *parser) acceptKeyword(kws ...string) bool {
+	for i, kw := range kws {
+		if !p.isKeyword(i, kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *parser) expectKeyword(kws ...string) {
+	if !p.acceptKeyword(kws...) {
+		p.fail(p.peek().Pos, "expected %s but got %q", strings.Join(kws, " "), p.peek().Text)
+	}
+}
+
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.Kind == tkOp && t.Text == op
+}
+
+func (p *parser) acceptOp(op string) bool {
+	if p.isOp(op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectOp(op string) {
+	if !p.acceptOp(op) {
+		p.fail(p.peek().Pos, "expected %q but got %q", op, p.peek().Text)
+	}
+}
+
+func (p *parser) isName() bool {
+	t := p.peek()
+	return t.Kind == tkQuotedIdent || t.Kind == tkIdent && !reservedWords[strings.ToUpper(t.Text)]
+}
+
+func (p *parser) name() string {
+	if !p.isName() {
+		p.fail(p.peek().Pos, "expected identifier but got %q", p.peek().Text)
+	}
+	return p.next().Text
+}
+
+// alias parses "[AS] alias" and returns an empty string if it doesn't exist.
+func (p *parser) alias() string {
+	if p.acceptKeyword("AS") {
+		return p.name()
+	}
+	if p.isName() {
+		return p.next().Text
+	}
+	return ""
+}
+
+func (p *parser) lookupTable(name string) Table {
+	for i := len(p.scopes) - 1; i >= 0; i-- {
+		if t, ok := p.scopes[i][name]; ok {
+			return t
+		}
+	}
+	return T(name)
+}
+
+func (p *parser) placeholder(t token) Expression {
+	i := p.argis[t.Pos]
+	if t.Text != "?" {
+		n, _ := strconv.Atoi(t.Text[1:])
+		i = n - 1
+	}
+	if i < 0 || i >= len(p.args) {
+		p.fail(t.Pos, "no argument for the placeholder %s", t.Text)
+	}
+	p.used[i] = true
+	return V(p.args[i])
+}
+
+// Parse parses SQL and creates a builder.
+//
+// It supports a subset of SQL: SELECT with joins, WHERE, GROUP BY, HAVING, ORDER BY, LIMIT and OFFSET,
+// INSERT INTO ... VALUES, UPDATE and DELETE.
+// Identifiers can be quoted by either double quotes or backquotes,
+// and the placeholders "?" and "$n" are bound to args.
+// All args must be used by the placeholders.
+// The string literals are written again with the quoting of the dialect to be rendered.
+// The returned builder can be modified further and rendered for any dialect.
+//
+// The meaning of the backslashes in the string literals depends on the dialect,
+// so Parse returns an error if they are found. Use ParseDialect to parse such SQL.
+func Parse(sql string, args ...interface{}) (Builder, error) {
+	return parse(sql, strictStrings, args)
+}
+
+// ParseDialect is the same as Parse but parses the string literals in the way of d.
+// In MySQL, the backslash escapes such as "\n" are decoded,
+// and the strings which are quoted by double quotes are the string literals instead of the identifiers.
+// "||" also means OR in MySQL, as MySQL does without PIPES_AS_CONCAT mode.
+// In the other dialects, the backslashes are the ordinary characters.
+func ParseDialect(d qutil.Dialect, sql string, args ...interface{}) (Builder, error) {
+	if d == MySQL {
+		return parse(sql, mySQLStrings, args)
+	}
+	return parse(sql, standardStrings, args)
+}
+
+func parse(sql string, mode stringMode, args []interface{}) (b Builder, err error) {
+	toks, err := tokenize(sql, mode)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, mode: mode, args: args, argis: map[int]int{}, used: make([]bool, len(args))}
+	// "?" is numbered in the order of the appearance,
+	// because some clauses such as FROM are parsed before the preceding clauses.
+	for _, t := range toks {
+		if t.Kind == tkPlaceholder && t.Text == "?" {
+			p.argis[t.Pos] = len(p.argis)
+		}
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			pe, ok := e.(*ParseError)
+			if !ok {
+				panic(e)
+			}
+			b, err = nil, pe
+		}
+	}()
+	switch {
+	case p.isKeyword(0, "SELECT"):
+		b = p.parseSelect()
+	case p.isKeyword(0, "INSERT"):
+		b = p.parseInsert()
+	case p.isKeyword(0, "UPDATE"):
+		b = p.parseUpdate()
+	case p.isKeyword(0, "DELETE"):
+		b = p.parseDelete()
+	default:
+		p.fail(p.peek().Pos, "unsupported statement %q", p.peek().Text)
+	}
+	p.acceptOp(";")
+	if t := p.peek(); t.Kind != tkEOF {
+		p.fail(t.Pos, "unexpected %q", t.Text)
+	}
+	for i, used := range p.used {
+		if !used {
+			p.fail(len(sql), "argument %d is not used by any placeholder", i+1)
+		}
+	}
+	return b, nil
+}
+
+// ParseSelect is the same as Parse but accepts SELECT statements only.
+func ParseSelect(sql string, args ...interface{}) (*ZSelectBuilder, error) {
+	b, err := Parse(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := b.(*ZSelectBuilder)
+	if !ok {
+		return nil, &ParseError{0, "not a SELECT statement"}
+	}
+	return sel, nil
+}
+
+func (p *parser) parseSelect() *ZSelectBuilder {
+	p.expectKeyword("SELECT")
+	b := Select()
+	if p.acceptKeyword("DISTINCT") {
+		b.Distinct()
+	}
+
+	p.scopes = append(p.scopes, map[string]Table{})
+	defer func() { p.scopes = p.scopes[:len(p.scopes)-1] }()
+
+	// FROM clause is parsed before the column list to resolve table aliases.
+	columnsPos, fromPos := p.pos, p.findFrom()
+	if fromPos != -1 {
+		p.pos = fromPos + 1
+		b.From(p.parseTableRef())
+		for p.acceptOp(",") {
+			b.From(p.parseTableRef())
+		}
+	}
+	endPos := p.pos
+	p.pos = columnsPos
+	if !p.acceptOp("*") {
+		b.Column(p.parseColumn())
+		for p.acceptOp(",") {
+			b.Column(p.parseColumn())
+		}
+	}
+	if fromPos != -1 {
+		if p.pos != fromPos {
+			p.fail(p.peek().Pos, "unexpected %q", p.peek().Text)
+		}
+		p.pos = endPos
+	}
+
+	if p.acceptKeyword("WHERE") {
+		b.Where(p.parseExpr())
+	}
+	if p.acceptKeyword("GROUP", "BY") {
+		b.GroupBy(p.parseExpr())
+		for p.acceptOp(",") {
+			b.GroupBy(p.parseExpr())
+		}
+	}
+	if p.acceptKeyword("HAVING") {
+		b.Having(p.parseExpr())
+	}
+	if p.acceptKeyword("ORDER", "BY") {
+		for {
+			o := Asc(p.parseExpr())
+			if p.acceptKeyword("COLLATE") {
+				o = o.Collate(p.name())
+			}
+			if p.acceptKeyword("DESC") {
+				o.Ascending = false
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			if p.acceptKeyword("NULLS", "FIRST") {
+				o = o.NullsFirst()
+			} else if p.acceptKeyword("NULLS", "LAST") {
+				o = o.NullsLast()
+			}
+			b.Order(o)
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		e := p.parseExpr()
+		if p.acceptOp(",") {
+			// MySQL style: LIMIT offset, count
+			b.Offset(e)
+			e = p.parseExpr()
+		}
+		b.Limit(e)
+	}
+	if p.acceptKeyword("OFFSET") {
+		b.Offset(p.parseExpr())
+	}
+	return b
+}
+
+// findFrom returns the position of FROM keyword which belongs to the current SELECT statement.
+func (p *parser) findFrom() int {
+	depth := 0
+	for i := p.pos; i < len(p.toks); i++ {
+		t := p.toks[i]
+		switch {
+		case t.Kind == tkEOF:
+			return -1
+		case t.Kind == tkOp && t.Text == "(":
+			depth++
+		case t.Kind == tkOp && t.Text == ")":
+			if depth == 0 {
+				return -1
+			}
+			depth--
+		case t.Kind == tkOp && t.Text == ";" && depth == 0:
+			return -1
+		case t.Kind == tkIdent && depth == 0 && strings.EqualFold(t.Text, "FROM"):
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *parser) parseColumn() Column {
+	if p.isName() && p.toks[p.pos+1].Kind == tkOp && p.toks[p.pos+1].Text == "." &&
+		p.toks[p.pos+2].Kind == tkOp && p.toks[p.pos+2].Text == "*" {
+		t := p.lookupTable(p.next().Text)
+		p.pos += 2
+		return &allColumns{Table: t}
+	}
+	e := p.parseExpr()
+	if alias := p.alias(); alias != "" {
+		return e.C(alias)
+	}
+	if c, ok := e.(Column); ok {
+		return c
+	}
+	return e.C()
+}
+
+func (p *parser) parseTablePrimary() Table {
+	var t Table
+	switch {
+	case p.isOp("(") && p.isKeyword(1, "SELECT"):
+		p.next()
+		sel := p.parseSelect()
+		p.expectOp(")")
+		p.acceptKeyword("AS")
+		t = sel.T(p.name())
+	case p.acceptOp("("):
+		t = p.parseTableRef()
+		p.expectOp(")")
+		return t
+	default:
+		name := p.name()
+		if p.isOp(".") {
+			p.fail(p.peek().Pos, "qualified table name is not supported")
+		}
+		if alias := p.alias(); alias != "" {
+			t = T(name, alias)
+			p.scopes[len(p.scopes)-1][alias] = t
+		} else {
+			t = T(name)
+			p.scopes[len(p.scopes)-1][name] = t
+		}
+		return t
+	}
+	p.scopes[len(p.scopes)-1][t.(*selectBuilderAsTable).Alias] = t
+	return t
+}
+
+func (p *parser) parseTableRef() Table {
+	t := p.parseTablePrimary()
+	for {
+		switch {
+		case p.acceptKeyword("CROSS", "JOIN"):
+			t = t.CrossJoin(p.parseTablePrimary())
+			continue
+		case p.acceptKeyword("JOIN"), p.acceptKeyword("INNER", "JOIN"):
+			jt := p.parseTablePrimary()
+			t = t.InnerJoin(jt, p.parseJoinCond()...)
+			continue
+		case p.acceptKeyword("LEFT", "JOIN"), p.acceptKeyword("LEFT", "OUTER", "JOIN"):
+			jt := p.parseTablePrimary()
+			t = t.LeftJoin(jt, p.parseJoinCond()...)
+			continue
+		}
+		return t
+	}
+}
+
+func (p *parser) parseJoinCond() []Expression {
+	if !p.acceptKeyword("ON") {
+		return nil
+	}
+	e := p.parseExpr()
+	if and, ok := e.(ZAndExpr); ok {
+		return and
+	}
+	return []Expression{e}
+}
+
+func (p *parser) parseInsert() *ZInsertBuilder {
+	p.expectKeyword("INSERT", "INTO")
+	b := Insert().Into(T(p.name()))
+	p.expectOp("(")
+	var cols []Column
+	for {
+		cols = append(cols, C(p.name()))
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	p.expectOp(")")
+	p.expectKeyword("VALUES")
+	p.expectOp("(")
+	for i, c := range cols {
+		if i > 0 {
+			p.expectOp(",")
+		}
+		b.Set(c, p.parseExpr())
+	}
+	p.expectOp(")")
+	if p.acceptKeyword("RETURNING") {
+		for {
+			b.Returning(p.parseColumn())
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	return b
+}
+
+func (p *parser) parseUpdate() *ZUpdateBuilder {
+	p.expectKeyword("UPDATE")
+	b := Update(T(p.name()))
+	p.expectKeyword("SET")
+	for {
+		c := C(p.name())
+		p.expectOp("=")
+		b.Set(c, p.parseExpr())
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	if p.acceptKeyword("WHERE") {
+		b.Where(p.parseExpr())
+	}
+	return b
+}
+
+func (p *parser) parseDelete() *ZDeleteBuilder {
+	p.expectKeyword("DELETE", "FROM")
+	b := Delete(T(p.name()))
+	if p.acceptKeyword("WHERE") {
+		b.Where(p.parseExpr())
+	}
+	return b
+}
+
+func (p *parser) parseExpr() Expression {
+	e := p.parseAnd()
+	if !p.acceptOr() {
+		return e
+	}
+	r := ZOrExpr{e, p.parseAnd()}
+	for p.acceptOr() {
+		r = append(r, p.parseAnd())
+	}
+	return r
+}
+
+// acceptOr accepts OR, or "||" in MySQL.
+func (p *parser) acceptOr() bool {
+	return p.acceptKeyword("OR") || p.mode == mySQLStrings && p.acceptOp("||")
+}
+
+func (p *parser) parseAnd() Expression {
+	e := p.parseNot()
+	if !p.isKeyword(0, "AND") {
+		return e
+	}
+	r := ZAndExpr{e}
+	for p.acceptKeyword("AND") {
+		r = append(r, p.parseNot())
+	}
+	return r
+}
+
+func (p *parser) parseNot() Expression {
+	if p.acceptKeyword("NOT") {
+		return Not(p.parseNot())
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() Expression {
+	if p.acceptKeyword("EXISTS") {
+		p.expectOp("(")
+		sel := p.parseSelect()
+		p.expectOp(")")
+		return Exists(sel)
+	}
+	l := p.parseAdditive()
+	t := p.peek()
+	if t.Kind == tkOp {
+		switch t.Text {
+		case "=":
+			p.next()
+			return Eq(l, p.parseComparand())
+		case "!=", "<>":
+			p.next()
+			return Neq(l, p.parseComparand())
+		case "<":
+			p.next()
+			return Lt(l, p.parseComparand())
+		case "<=":
+			p.next()
+			return Lte(l, p.parseComparand())
+		case ">":
+			p.next()
+			return Gt(l, p.parseComparand())
+		case ">=":
+			p.next()
+			return Gte(l, p.parseComparand())
+		}
+		return l
+	}
+	switch {
+	case p.acceptKeyword("IS", "NOT", "DISTINCT", "FROM"):
+		return NullSafeEq(l, p.parseAdditive())
+	case p.acceptKeyword("IS", "DISTINCT", "FROM"):
+		return NullSafeNeq(l, p.parseAdditive())
+	case p.acceptKeyword("IS", "NOT", "NULL"):
+		return Neq(l, nil)
+	case p.acceptKeyword("IS", "NULL"):
+		return Eq(l, nil)
+	case p.acceptKeyword("NOT", "IN"):
+		return p.parseIn(l, NotIn)
+	case p.acceptKeyword("IN"):
+		return p.parseIn(l, In)
+	case p.acceptKeyword("NOT", "ILIKE"):
+		return NotILike(l, p.parseAdditive())
+	case p.acceptKeyword("ILIKE"):
+		return ILike(l, p.parseAdditive())
+	case p.acceptKeyword("NOT", "LIKE"):
+		return NotLike(l, p.parseAdditive())
+	case p.acceptKeyword("LIKE"):
+		return Like(l, p.parseAdditive())
+	case p.acceptKeyword("NOT", "BETWEEN"):
+		from := p.parseAdditive()
+		p.expectKeyword("AND")
+		return NotBetween(l, from, p.parseAdditive())
+	case p.acceptKeyword("BETWEEN"):
+		from := p.parseAdditive()
+		p.expectKeyword("AND")
+		return Between(l, from, p.parseAdditive())
+	}
+	return l
+}
+
+// parseComparand parses the right-hand side of the comparison operators
+// which may be quantified by ANY, SOME or ALL.
+func (p *parser) parseComparand() Expression {
+	for _, kw := range []string{"ANY", "SOME", "ALL"} {
+		if !p.acceptKeyword(kw) {
+			continue
+		}
+		p.expectOp("(")
+		var v interface{}
+		if p.isKeyword(0, "SELECT") {
+			v = p.parseSelect()
+		} else {
+			v = p.parseAdditive()
+		}
+		p.expectOp(")")
+		switch kw {
+		case "ANY":
+			return Any(v)
+		case "SOME":
+			return Some(v)
+		}
+		return All(v)
+	}
+	return p.parseAdditive()
+}
+
+func (p *parser) parseIn(l Expression, in func(l, r interface{}) Expression) Expression {
+	p.expectOp("(")
+	if p.isKeyword(0, "SELECT") {
+		sel := p.parseSelect()
+		p.expectOp(")")
+		return in(l, sel)
+	}
+	var exprs []Expression
+	for {
+		exprs = append(exprs, p.parseAdditive())
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	p.expectOp(")")
+
+	// When all elements are bound variables, it is converted to the list of values
+	// so that the list can be handled as the same as Eq(l, slice).
+	values := make([]interface{}, len(exprs))
+	for i, e := range exprs {
+		v, ok := e.(*variable)
+		if !ok {
+			values = nil
+			break
+		}
+		values[i] = v.V
+	}
+	if values != nil {
+		return in(l, values)
+	}
+	u := unsafeExpr{"("}
+	for i, e := range exprs {
+		if i > 0 {
+			u = append(u, ", ")
+		}
+		u = append(u, e)
+	}
+	return in(l, append(u, ")"))
+}
+
+func (p *parser) parseAdditive() Expression {
+	e := p.parseMultiplicative()
+	for {
+		t := p.peek()
+		if t.Kind != tkOp || t.Text != "+" && t.Text != "-" && (t.Text != "||" || p.mode == mySQLStrings) {
+			return e
+		}
+		p.next()
+		r := p.parseMultiplicative()
+		switch t.Text {
+		case "+":
+			e = Add(e, r)
+		case "-":
+			e = Sub(e, r)
+		default:
+			if c, ok := e.(*concatExpr); ok {
+				e = &concatExpr{Values: append(c.Values, r)}
+			} else {
+				e = Concat(e, r)
+			}
+		}
+	}
+}
+
+func (p *parser) parseMultiplicative() Expression {
+	e := p.parseUnary()
+	for {
+		t := p.peek()
+		if t.Kind != tkOp || t.Text != "*" && t.Text != "/" && t.Text != "%" {
+			return e
+		}
+		p.next()
+		r := p.parseUnary()
+		switch t.Text {
+		case "*":
+			e = Mul(e, r)
+		case "/":
+			e = Div(e, r)
+		default:
+			e = Mod(e, r)
+		}
+	}
+}
+
+func (p *parser) parseUnary() Expression {
+	if p.acceptOp("-") {
+		return Neg(p.parseUnary())
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() Expression {
+	t := p.peek()
+	switch t.Kind {
+	case tkNumber:
+		p.next()
+		return Unsafe(t.Text)
+	case tkString:
+		p.next()
+		return stringLiteral(t.Text)
+	case tkPlaceholder:
+		p.next()
+		return p.placeholder(t)
+	case tkOp:
+		if t.Text != "(" {
+			break
+		}
+		p.next()
+		if p.isKeyword(0, "SELECT") {
+			sel := p.parseSelect()
+			p.expectOp(")")
+			return sel
+		}
+		e := p.parseExpr()
+		p.expectOp(")")
+		if _, ok := e.(ZOrExpr); ok {
+			return e
+		}
+		if _, ok := e.(ZAndExpr); ok {
+			return e
+		}
+		if operatorPrecedence(e) != 0 {
+			// Parentheses are written again if the operator precedence requires.
+			return e
+		}
+		return Unsafe("(", e, ")")
+	case tkIdent, tkQuotedIdent:
+		switch {
+		case p.acceptKeyword("NULL"):
+			return nullExpr{}
+		case p.acceptKeyword("CASE"):
+			return p.parseCase()
+		case p.isKeyword(0, "TRUE"), p.isKeyword(0, "FALSE"):
+			p.next()
+			return Unsafe(strings.ToUpper(t.Text))
+		case p.isKeyword(0, "CURRENT_TIMESTAMP"):
+			p.next()
+			return Now()
+		case t.Kind == tkIdent && p.toks[p.pos+1].Kind == tkOp && p.toks[p.pos+1].Text == "(":
+			p.next()
+			return p.parseFunction(t.Text)
+		}
+		if !p.isName() {
+			break
+		}
+		p.next()
+		if p.acceptOp(".") {
+			return p.lookupTable(t.Text).C(p.name())
+		}
+		return C(t.Text)
+	}
+	p.fail(t.Pos, "unexpected %q", t.Text)
+	return nil
+}
+
+func (p *parser) parseFunction(name string) Expression {
+	p.expectOp("(")
+	upper := strings.ToUpper(name)
+	if upper == "COUNT" && p.acceptOp("*") {
+		p.expectOp(")")
+		return CountAll()
+	}
+	var args []Expression
+	distinct := p.acceptKeyword("DISTINCT")
+	if distinct || !p.isOp(")") {
+		for {
+			args = append(args, p.parseExpr())
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	p.expectOp(")")
+	if distinct {
+		// such as "COUNT(DISTINCT a)" and "GROUP_CONCAT(DISTINCT a, b)"
+		args[0] = Distinct(args[0])
+	}
+	if len(args) == 1 {
+		switch upper {
+		case "COUNT":
+			return Count(args[0])
+		case "AVG":
+			return Avg(args[0])
+		case "MAX":
+			return Max(args[0])
+		case "MIN":
+			return Min(args[0])
+		case "SUM":
+			return Sum(args[0])
+		case "CHAR_LENGTH", "CHARACTER_LENGTH":
+			return CharLength(args[0])
+		}
+	}
+	if upper == "NOW" && len(args) == 0 {
+		return Now()
+	}
+	u := unsafeExpr{name + "("}
+	for i, a := range args {
+		if i > 0 {
+			u = append(u, ", ")
+		}
+		u = append(u, a)
+	}
+	return append(u, ")")
+}
+
+func (p *parser) parseCase() Expression {
+	var b *ZCaseBuilder
+	if p.isKeyword(0, "WHEN") {
+		b = Case()
+	} else {
+		b = Case(p.parseExpr())
+	}
+	for p.acceptKeyword("WHEN") {
+		cond := p.parseExpr()
+		p.expectKeyword("THEN")
+		b.When(cond, p.parseExpr())
+	}
+	if p.acceptKeyword("ELSE") {
+		b.Else(p.parseExpr())
+	}
+	p.expectKeyword("END")
+	return b
+}
+
+// stringLiteral represents the string literal in the parsed SQL,
+// which is written with the quoting of the dialect.
+type stringLiteral string
+
+func (s stringLiteral) String() string               { return expressionToString(s) }
+func (s stringLiteral) C(aliasName ...string) Column { return columnExpr(s, aliasName...) }
+func (s stringLiteral) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return ctx.Dialect.QuoteString(buf, string(s))
+}
+
+// allColumns represents "table.*".
+type allColumns struct {
+	Table Table
+}
+
+func (c *allColumns) String() string               { return columnToString(c) }
+func (c *allColumns) C(aliasName ...string) Column { return c }
+func (c *allColumns) WriteColumn(ctx *qutil.Context, buf []byte) []byte {
+	buf = c.Table.WriteTable(ctx, buf)
+	return append(buf, ".*"...)
+}
+func (c *allColumns) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return c.WriteColumn(ctx, buf)
+}
+func (c *allColumns) WriteDefinition(ctx *qutil.Context, buf []byte) []byte {
+	return c.WriteColumn(ctx, buf)
+}
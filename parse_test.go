@@ -0,0 +1,233 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+var parseTests = []struct {
+	Name string
+	SQL  string
+	Args []interface{}
+	V    string
+}{
+	{
+		Name: "simple select",
+		SQL:  `SELECT * FROM user`,
+		V:    `SELECT * FROM "user" []`,
+	},
+	{
+		Name: "columns and alias",
+		SQL:  "SELECT u.id, u.name AS n, COUNT(*) cnt FROM `user` u WHERE u.age >= ? AND u.name != 'x' GROUP BY u.id, u.name HAVING COUNT(*) > 1 ORDER BY u.id DESC, n LIMIT 10 OFFSET ?",
+		Args: []interface{}{18, 20},
+		V:    `SELECT "u"."id", "u"."name" AS "n", COUNT(*) AS "cnt" FROM "user" AS "u" WHERE ("u"."age" >= ?)AND("u"."name" != 'x') GROUP BY "u"."id", "u"."name" HAVING COUNT(*) > 1 ORDER BY "u"."id" DESC, "n" ASC LIMIT 10 OFFSET ? [18 20]`,
+	},
+	{
+		Name: "joins",
+		SQL:  `SELECT p.*, u.name FROM post AS p INNER JOIN "user" AS u ON p.user_id = u.id AND u.age > 10 LEFT OUTER JOIN tag t ON t.id = p.id CROSS JOIN x`,
+		V:    `SELECT "p".*, "u"."name" FROM "post" AS "p" INNER JOIN "user" AS "u" ON ("p"."user_id" = "u"."id")AND("u"."age" > 10) LEFT JOIN "tag" AS "t" ON "t"."id" = "p"."id" CROSS JOIN "x" []`,
+	},
+	{
+		Name: "placeholders in columns and joins",
+		SQL:  `SELECT a + ? AS x FROM t JOIN u ON u.k = ? WHERE t.id = ?`,
+		Args: []interface{}{1, 2, 3},
+		V:    `SELECT "a" + ? AS "x" FROM "t" INNER JOIN "u" ON "u"."k" = ? WHERE "t"."id" = ? [1 2 3]`,
+	},
+	{
+		Name: "placeholders in columns and subquery",
+		SQL:  `SELECT ?, (SELECT b FROM u WHERE c = ?) FROM (SELECT * FROM v WHERE d = ?) s WHERE e = ?`,
+		Args: []interface{}{1, 2, 3, 4},
+		V:    `SELECT ?, (SELECT "b" FROM "u" WHERE "c" = ?) FROM (SELECT * FROM "v" WHERE "d" = ?) AS "s" WHERE "e" = ? [1 2 3 4]`,
+	},
+	{
+		Name: "distinct aggregates",
+		SQL:  `SELECT COUNT(DISTINCT a), SUM(DISTINCT b), GROUP_CONCAT(DISTINCT c, d) FROM t`,
+		V:    `SELECT COUNT(DISTINCT "a"), SUM(DISTINCT "b"), GROUP_CONCAT(DISTINCT "c", "d") FROM "t" []`,
+	},
+	{
+		Name: "concat",
+		SQL:  `SELECT a || b || 'c' FROM t WHERE a = 1 OR b = 2`,
+		V:    `SELECT "a" || "b" || 'c' FROM "t" WHERE ("a" = 1)OR("b" = 2) []`,
+	},
+	{
+		Name: "subqueries",
+		SQL:  `SELECT s.c FROM (SELECT COUNT(id) AS c FROM post) s WHERE s.c IN (SELECT id FROM user WHERE user.id = s.c) AND EXISTS (SELECT 1)`,
+		V:    `SELECT "s"."c" FROM (SELECT COUNT("id") AS "c" FROM "post") AS "s" WHERE ("s"."c" IN (SELECT "id" FROM "user" WHERE "user"."id" = "s"."c"))AND(EXISTS (SELECT 1)) []`,
+	},
+	{
+		Name: "predicates",
+		SQL:  `SELECT id FROM user WHERE (a IS NULL OR b IS NOT NULL) AND c IN (?, ?) AND d NOT IN (1, 2) AND e LIKE 'a%' AND NOT f BETWEEN 1 AND 2 AND g = NULL`,
+		Args: []interface{}{1, 2},
+		V:    `SELECT "id" FROM "user" WHERE (("a" IS NULL)OR("b" IS NOT NULL))AND("c" IN (?,?))AND("d" NOT IN (1, 2))AND("e" LIKE 'a%')AND(NOT ("f" BETWEEN 1 AND 2))AND("g" = NULL) [1 2]`,
+	},
+	{
+		Name: "expressions",
+		SQL:  `SELECT -a + b * (c - 1) AS x, CASE WHEN a = 1 THEN 'one' ELSE 'other' END, CASE b WHEN 1 THEN TRUE END, COALESCE(a, b), CURRENT_TIMESTAMP FROM t`,
+		V:    `SELECT -"a" + "b" * ("c" - 1) AS "x", CASE WHEN "a" = 1 THEN 'one' ELSE 'other' END, CASE "b" WHEN 1 THEN TRUE END, COALESCE("a", "b"), CURRENT_TIMESTAMP FROM "t" []`,
+	},
+	{
+		Name: "quantified",
+		SQL:  `SELECT * FROM t WHERE a > ALL (SELECT b FROM u) AND c = ANY (?)`,
+		Args: []interface{}{[]int{1, 2}},
+		V:    `SELECT * FROM "t" WHERE ("a" > ALL (SELECT "b" FROM "u"))AND("c" = ANY (?)) [[1 2]]`,
+	},
+	{
+		Name: "ilike",
+		SQL:  `SELECT * FROM t WHERE a ILIKE ? AND b NOT ILIKE 'x%'`,
+		Args: []interface{}{"a%"},
+		V:    `SELECT * FROM "t" WHERE (LOWER("a") LIKE LOWER(?))AND(LOWER("b") NOT LIKE LOWER('x%')) [a%]`,
+	},
+	{
+		Name: "distinct from",
+		SQL:  `SELECT * FROM t WHERE a IS DISTINCT FROM ? AND b IS NOT DISTINCT FROM c`,
+		Args: []interface{}{1},
+		V:    `SELECT * FROM "t" WHERE ("a" IS DISTINCT FROM ?)AND("b" IS NOT DISTINCT FROM "c") [1]`,
+	},
+	{
+		Name: "MySQL limit",
+		SQL:  `SELECT * FROM t LIMIT 20, 10;`,
+		V:    `SELECT * FROM "t" LIMIT 10 OFFSET 20 []`,
+	},
+	{
+		Name: "PostgreSQL placeholders",
+		SQL:  `SELECT * FROM t WHERE a = $2 AND b = $1`,
+		Args: []interface{}{"x", "y"},
+		V:    `SELECT * FROM "t" WHERE ("a" = ?)AND("b" = ?) [y x]`,
+	},
+	{
+		Name: "insert",
+		SQL:  `INSERT INTO user (id, name) VALUES (?, 'x') RETURNING id`,
+		Args: []interface{}{1},
+		V:    `INSERT INTO "user"("id", "name") VALUES (?, 'x') RETURNING "id" [1]`,
+	},
+	{
+		Name: "update",
+		SQL:  `UPDATE user SET name = ?, age = age + 1 WHERE id = ?`,
+		Args: []interface{}{"x", 1},
+		V:    `UPDATE "user" SET "name" = ?, "age" = "age" + 1 WHERE "id" = ? [x 1]`,
+	},
+	{
+		Name: "order by with nulls and collation",
+		SQL:  `SELECT id FROM user ORDER BY name COLLATE "C" DESC NULLS LAST, score NULLS FIRST`,
+		V:    `SELECT "id" FROM "user" ORDER BY "name" COLLATE "C" DESC NULLS LAST, "score" ASC NULLS FIRST []`,
+	},
+	{
+		Name: "delete",
+		SQL:  `delete from user where id = 1 -- comment`,
+		V:    `DELETE FROM "user" WHERE "id" = 1 []`,
+	},
+}
+
+func TestParse(t *testing.T) {
+	for i, test := range parseTests {
+		b, err := Parse(test.SQL, test.Args...)
+		if err != nil {
+			t.Errorf("tests[%d] %s: %v", i, test.Name, err)
+			continue
+		}
+		if r := fmt.Sprint(b); r != test.V {
+			t.Errorf("tests[%d] %s: want %s got %s", i, test.Name, test.V, r)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for i, sql := range []string{
+		``,
+		`SELECT`,
+		`SELECT * FROM`,
+		`SELECT * FROM t WHERE`,
+		`SELECT * FROM t WHERE a = ?`,
+		`SELECT * FROM t WHERE a = 'x`,
+		`SELECT * FROM t extra garbage`,
+		`SELECT * FROM s.t`,
+		`UPDATE t`,
+		`MERGE INTO t`,
+		`SELECT * FROM t WHERE a = #`,
+		`SELECT * FROM t WHERE d = any`,
+		`SELECT * FROM t WHERE d = ALL`,
+		`SELECT * FROM t WHERE a = 'it\'s'`,
+		`SELECT * FROM t WHERE a = ? AND b = ?`,
+		`SELECT COUNT(DISTINCT) FROM t`,
+	} {
+		if _, err := Parse(sql); err == nil {
+			t.Errorf("tests[%d] %q want error got nil", i, sql)
+		} else if _, ok := err.(*ParseError); !ok {
+			t.Errorf("tests[%d] %q want *ParseError got %T", i, sql, err)
+		}
+	}
+	if _, err := Parse(`SELECT * FROM t WHERE a = ?`, 1, 2); err == nil {
+		t.Errorf("want error for the unused argument got nil")
+	}
+	if _, err := Parse(`SELECT * FROM t WHERE a = $2`, 1, 2); err == nil {
+		t.Errorf("want error for the unused argument got nil")
+	}
+	if _, err := ParseSelect(`DELETE FROM t`); err == nil {
+		t.Errorf("ParseSelect want error got nil")
+	}
+}
+
+func TestParseModifyAndTranslate(t *testing.T) {
+	sel, err := ParseSelect("SELECT `u`.`id`, `u`.`name` FROM `user` AS `u` WHERE `u`.`age` >= ? LIMIT ?", 18, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel.Where(Eq(C("deleted"), false)).SetDialect(PostgreSQL)
+	sql, args := sel.ToSQL()
+	if want := `SELECT "u"."id", "u"."name" FROM "user" AS "u" WHERE ("u"."age" >= $1)AND("deleted" = $2) LIMIT $3`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if want, got := `[18 false 10]`, fmt.Sprint(args); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	for i, test := range []struct {
+		D                 qutil.Dialect
+		SQL               string
+		MySQL, PostgreSQL string
+	}{
+		{
+			D:          MySQL,
+			SQL:        `SELECT * FROM t WHERE a = "it's" AND b = 'a\'b\\c\nd' AND c LIKE '10\%'`,
+			MySQL:      "SELECT * FROM `t` WHERE (`a` = 'it''s')AND(`b` = 'a''b\\\\c\nd')AND(`c` LIKE '10\\\\%')",
+			PostgreSQL: `SELECT * FROM "t" WHERE ("a" = 'it''s')AND("b" = 'a''b\c` + "\n" + `d')AND("c" LIKE '10\%')`,
+		},
+		{
+			D:          MySQL,
+			SQL:        `SELECT a || b FROM t WHERE c = 1 || d = 2`,
+			MySQL:      "SELECT (`a`)OR(`b`) FROM `t` WHERE (`c` = 1)OR(`d` = 2)",
+			PostgreSQL: `SELECT ("a")OR("b") FROM "t" WHERE ("c" = 1)OR("d" = 2)`,
+		},
+		{
+			D:          PostgreSQL,
+			SQL:        `SELECT a || b FROM t`,
+			MySQL:      "SELECT CONCAT(`a`, `b`) FROM `t`",
+			PostgreSQL: `SELECT "a" || "b" FROM "t"`,
+		},
+		{
+			D:          PostgreSQL,
+			SQL:        `SELECT "a" FROM t WHERE b = 'C:\dir' AND c = 'it''s'`,
+			MySQL:      "SELECT `a` FROM `t` WHERE (`b` = 'C:\\\\dir')AND(`c` = 'it''s')",
+			PostgreSQL: `SELECT "a" FROM "t" WHERE ("b" = 'C:\dir')AND("c" = 'it''s')`,
+		},
+	} {
+		b, err := ParseDialect(test.D, test.SQL)
+		if err != nil {
+			t.Errorf("tests[%d] %v", i, err)
+			continue
+		}
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}} {
+			sel := b.(*ZSelectBuilder).SetDialect(d.D)
+			if sql, _ := sel.ToSQL(); sql != d.Want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, d.Want, sql)
+			}
+		}
+	}
+}
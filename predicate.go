@@ -0,0 +1,344 @@
+package q
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/oov/q/qutil"
+)
+
+// Not creates Expression such as "NOT (e)".
+func Not(e Expression) Expression {
+	return &notExpr{Expression: e}
+}
+
+type notExpr struct {
+	Expression
+}
+
+func (e *notExpr) String() string               { return expressionToString(e) }
+func (e *notExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *notExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, "NOT ("...)
+	buf = e.Expression.WriteExpression(ctx, buf)
+	return append(buf, ')')
+}
+
+// Exists creates Expression such as "EXISTS (SELECT ...)".
+func Exists(sel *ZSelectBuilder) Expression {
+	return &existsExpr{Select: sel}
+}
+
+// NotExists creates Expression such as "NOT EXISTS (SELECT ...)".
+func NotExists(sel *ZSelectBuilder) Expression {
+	return &existsExpr{Not: true, Select: sel}
+}
+
+type existsExpr struct {
+	Not    bool
+	Select *ZSelectBuilder
+}
+
+func (e *existsExpr) String() string               { return expressionToString(e) }
+func (e *existsExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *existsExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if e.Not {
+		buf = append(buf, "NOT "...)
+	}
+	buf = append(buf, "EXISTS "...)
+	return writeSubquery(e.Select, ctx, buf)
+}
+
+// Quantifier represents the right-hand side of the quantified comparison such as "= ANY (...)".
+// It is created by Any, Some or All, and can be passed to r of Eq, Neq, Gt, Gte, Lt and Lte.
+type Quantifier Expression
+
+// Any creates Quantifier such as "ANY (v)".
+//
+// v is *ZSelectBuilder, Expression or slice of any type.
+// If v is slice, the comparison is expanded such as "col IN (?,?)" or "(col > ?)OR(col > ?)".
+// If v is created by Array, it is passed as a single array argument in the dialect which supports it
+// such as PostgreSQL, so Eq(col, Any(Array(slice))) creates "col = ANY ($1)" whose SQL doesn't depend on the length of the slice.
+func Any(v interface{}) Quantifier {
+	return &quantifier{Name: "ANY", V: v}
+}
+
+// Some is the same as Any but it is written as "SOME (v)".
+func Some(v interface{}) Quantifier {
+	return &quantifier{Name: "SOME", V: v}
+}
+
+// All creates Quantifier such as "ALL (v)".
+//
+// v is the same as Any.
+// If v is slice, or v is created by Array and the dialect doesn't support array arguments,
+// the comparison is expanded such as "col NOT IN (?,?)" or "(col > ?)AND(col > ?)".
+func All(v interface{}) Quantifier {
+	return &quantifier{Name: "ALL", V: v}
+}
+
+type quantifier struct {
+	Name string
+	V    interface{}
+}
+
+func (q *quantifier) String() string               { return expressionToString(q) }
+func (q *quantifier) C(aliasName ...string) Column { return columnExpr(q, aliasName...) }
+func (q *quantifier) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, q.Name...)
+	buf = append(buf, ' ')
+	if sel, ok := q.V.(*ZSelectBuilder); ok {
+		return writeSubquery(sel, ctx, buf)
+	}
+	buf = append(buf, '(')
+	buf = writeIntf(q.V, ctx, buf)
+	return append(buf, ')')
+}
+
+// values returns the elements if V is slice which is not Expression,
+// or V is created by Array and the dialect doesn't support array arguments.
+func (q *quantifier) values(d qutil.Dialect) (inVariable, bool) {
+	switch v := q.V.(type) {
+	case *arrayExpr:
+		if d.CanUseArrayParameter() {
+			return nil, false
+		}
+		return valueToInV(reflect.ValueOf(v.V)), true
+	case Expression:
+		return nil, false
+	}
+	if rv := reflect.ValueOf(q.V); rv.Kind() == reflect.Slice {
+		return valueToInV(rv), true
+	}
+	return nil, false
+}
+
+// quantified returns the quantified comparison if r is Quantifier,
+// or r is created by Array and op is "=" or "!=".
+func quantified(l interface{}, op string, r interface{}) (Expression, bool) {
+	switch v := r.(type) {
+	case *quantifier:
+		return &quantifiedExpr{Left: l, Op: op, Right: v}, true
+	case *arrayExpr:
+		switch op {
+		case "=":
+			return &quantifiedExpr{Left: l, Op: op, Right: &quantifier{Name: "ANY", V: v}}, true
+		case "!=":
+			return &quantifiedExpr{Left: l, Op: op, Right: &quantifier{Name: "ALL", V: v}}, true
+		}
+	}
+	return nil, false
+}
+
+type quantifiedExpr struct {
+	Left  interface{}
+	Op    string
+	Right *quantifier
+}
+
+func (e *quantifiedExpr) String() string               { return expressionToString(e) }
+func (e *quantifiedExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *quantifiedExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	values, ok := e.Right.values(ctx.Dialect)
+	if !ok {
+		buf = writeIntf(e.Left, ctx, buf)
+		buf = append(buf, ' ')
+		buf = append(buf, e.Op...)
+		buf = append(buf, ' ')
+		return e.Right.WriteExpression(ctx, buf)
+	}
+
+	all := e.Right.Name == "ALL"
+	switch {
+	case len(values) == 0:
+		// ANY with empty array is always false, ALL with empty array is always true.
+		return append(buf, ctx.Dialect.BoolLiteral(all)...)
+	case e.Op == "=" && !all:
+		return (&inExpr{Left: e.Left, Right: values}).WriteExpression(ctx, buf)
+	case e.Op == "!=" && all:
+		return (&notInExpr{Left: e.Left, Right: values}).WriteExpression(ctx, buf)
+	}
+	exprs := make([]Expression, len(values))
+	for i, v := range values {
+		exprs[i] = unsafeExpr{interfaceToExpression(e.Left), " " + e.Op + " ", V(v)}
+	}
+	if all {
+		return ZAndExpr(exprs).WriteExpression(ctx, buf)
+	}
+	return ZOrExpr(exprs).WriteExpression(ctx, buf)
+}
+
+// Array creates Expression which passes the slice v as a single array argument such as "$1"
+// in the dialect which supports it such as PostgreSQL.
+// The argument is driver.Valuer which converts v into the array literal such as "{1,2,3}".
+// In the other dialects, it is written as "(?,?,?)".
+//
+// It is mainly used with Any and All, such as Eq(col, Any(Array(slice))).
+// Eq(col, Array(slice)) and Neq(col, Array(slice)) are the same as using Any and All respectively.
+// Elements of v must be nil, bool, integer, float, string, time.Time or driver.Valuer which returns one of them.
+func Array(v interface{}) Expression {
+	if reflect.ValueOf(v).Kind() != reflect.Slice {
+		panic("q: Array needs a slice.")
+	}
+	return &arrayExpr{V: v}
+}
+
+type arrayExpr struct {
+	V interface{}
+}
+
+func (e *arrayExpr) String() string               { return expressionToString(e) }
+func (e *arrayExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *arrayExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if !ctx.Dialect.CanUseArrayParameter() {
+		return valueToInV(reflect.ValueOf(e.V)).WriteExpression(ctx, buf)
+	}
+	ctx.Args = append(ctx.Args, arrayValue{V: e.V})
+	return ctx.Placeholder.Next(buf)
+}
+
+// arrayValue is driver.Valuer which converts the slice into the array literal such as "{1,2,3}".
+type arrayValue struct {
+	V interface{}
+}
+
+func (a arrayValue) String() string {
+	v, err := a.Value()
+	if err != nil {
+		return fmt.Sprint(a.V)
+	}
+	return fmt.Sprint(v)
+}
+
+func (a arrayValue) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(a.V)
+	if rv.IsNil() {
+		return nil, nil
+	}
+	buf := []byte{'{'}
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		if buf, err = appendArrayElement(buf, rv.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return string(append(buf, '}')), nil
+}
+
+func appendArrayElement(buf []byte, v interface{}) ([]byte, error) {
+	if vr, ok := v.(driver.Valuer); ok {
+		var err error
+		if v, err = vr.Value(); err != nil {
+			return nil, err
+		}
+	}
+	if v == nil {
+		return append(buf, "NULL"...), nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return appendArrayString(buf, t.Format(time.RFC3339Nano)), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return append(buf, "NULL"...), nil
+		}
+		return appendArrayElement(buf, rv.Elem().Interface())
+	case reflect.Bool:
+		return strconv.AppendBool(buf, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(buf, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(buf, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(buf, rv.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return appendArrayString(buf, rv.String()), nil
+	}
+	return nil, fmt.Errorf("q: unsupported array element type %T", v)
+}
+
+// appendArrayString appends s with double quotes, and escapes '"' and '\\' with a backslash.
+func appendArrayString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
+
+// Between creates Expression such as "v BETWEEN from AND to".
+func Between(v, from, to interface{}) Expression {
+	return &betweenExpr{V: v, From: from, To: to}
+}
+
+// NotBetween creates Expression such as "v NOT BETWEEN from AND to".
+func NotBetween(v, from, to interface{}) Expression {
+	return &betweenExpr{V: v, From: from, To: to, Not: true}
+}
+
+type betweenExpr struct {
+	V    interface{}
+	From interface{}
+	To   interface{}
+	Not  bool
+}
+
+func (e *betweenExpr) String() string               { return expressionToString(e) }
+func (e *betweenExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *betweenExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = writeIntf(e.V, ctx, buf)
+	if e.Not {
+		buf = append(buf, " NOT"...)
+	}
+	buf = append(buf, " BETWEEN "...)
+	buf = writeIntf(e.From, ctx, buf)
+	buf = append(buf, " AND "...)
+	return writeIntf(e.To, ctx, buf)
+}
+
+// NullSafeEq creates Expression such as "l IS NOT DISTINCT FROM r",
+// which is true if both are NULL and false if only one of them is NULL.
+//
+// It is written as "l IS NOT DISTINCT FROM r" in PostgreSQL, "l <=> r" in MySQL and "l IS r" in SQLite.
+// Unlike Eq, it works correctly even if the argument which is passed to r is nil at runtime.
+// When you pass nil to one of a pair, NullSafeEq creates "x IS NULL" the same as Eq.
+func NullSafeEq(l, r interface{}) Expression {
+	return &nullSafeEqExpr{Left: l, Right: r}
+}
+
+// NullSafeNeq creates Expression such as "l IS DISTINCT FROM r".
+//
+// It is written as "l IS DISTINCT FROM r" in PostgreSQL, "NOT (l <=> r)" in MySQL and "l IS NOT r" in SQLite.
+// When you pass nil to one of a pair, NullSafeNeq creates "x IS NOT NULL" the same as Neq.
+func NullSafeNeq(l, r interface{}) Expression {
+	return &nullSafeEqExpr{Left: l, Right: r, Not: true}
+}
+
+type nullSafeEqExpr struct {
+	Left  interface{}
+	Right interface{}
+	Not   bool
+}
+
+func (e *nullSafeEqExpr) String() string               { return expressionToString(e) }
+func (e *nullSafeEqExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *nullSafeEqExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if e.Left == nil || e.Right == nil {
+		if e.Not {
+			return neqExpr{Left: e.Left, Right: e.Right}.WriteExpression(ctx, buf)
+		}
+		return eqExpr{Left: e.Left, Right: e.Right}.WriteExpression(ctx, buf)
+	}
+	return ctx.Dialect.NullSafeEq(ctx, buf, e.Left, e.Right, e.Not)
+}
@@ -0,0 +1,341 @@
+package q
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/oov/q/qutil"
+)
+
+var predicateTests = []struct {
+	Name string
+	E    Expression
+	D    qutil.Dialect
+	SQL  string
+	Args string
+}{
+	{
+		Name: "not",
+		E:    Not(Or(Eq(C("a"), 1), Eq(C("b"), nil))),
+		D:    MySQL,
+		SQL:  "NOT ((`a` = ?)OR(`b` IS NULL))",
+		Args: "[1]",
+	},
+	{
+		Name: "exists",
+		E:    Exists(Select().From(T("post")).Where(Eq(T("post").C("user_id"), T("user").C("id")))),
+		D:    PostgreSQL,
+		SQL:  `EXISTS (SELECT * FROM "post" WHERE "post"."user_id" = "user"."id")`,
+		Args: "[]",
+	},
+	{
+		Name: "not exists",
+		E:    NotExists(Select().From(T("post")).Where(Eq(C("id"), 1))),
+		D:    SQLite,
+		SQL:  `NOT EXISTS (SELECT * FROM "post" WHERE "id" = ?)`,
+		Args: "[1]",
+	},
+	{
+		Name: "any subquery",
+		E:    Gt(C("age"), Any(Select().Column(C("age")).From(T("user")))),
+		D:    MySQL,
+		SQL:  "`age` > ANY (SELECT `age` FROM `user`)",
+		Args: "[]",
+	},
+	{
+		Name: "some subquery",
+		E:    Eq(C("id"), Some(Select().Column(C("id")).From(T("user")))),
+		D:    PostgreSQL,
+		SQL:  `"id" = SOME (SELECT "id" FROM "user")`,
+		Args: "[]",
+	},
+	{
+		Name: "all subquery",
+		E:    Lte(C("age"), All(Select().Column(C("age")).From(T("user")).Where(Eq(C("id"), 1)))),
+		D:    SQLite,
+		SQL:  `"age" <= ALL (SELECT "age" FROM "user" WHERE "id" = ?)`,
+		Args: "[1]",
+	},
+	{
+		Name: "any slice",
+		E:    Eq(C("id"), Any([]int{1, 2, 3})),
+		D:    PostgreSQL,
+		SQL:  `"id" IN ($1,$2,$3)`,
+		Args: "[1 2 3]",
+	},
+	{
+		Name: "any array",
+		E:    Eq(C("id"), Any(Array([]int{1, 2, 3}))),
+		D:    PostgreSQL,
+		SQL:  `"id" = ANY ($1)`,
+		Args: "[{1,2,3}]",
+	},
+	{
+		Name: "all array",
+		E:    Neq(C("id"), All(Array([]int{1, 2}))),
+		D:    PostgreSQL,
+		SQL:  `"id" != ALL ($1)`,
+		Args: "[{1,2}]",
+	},
+	{
+		Name: "eq array",
+		E:    Eq(C("name"), Array([]interface{}{`a"b`, `c\d`, nil})),
+		D:    PostgreSQL,
+		SQL:  `"name" = ANY ($1)`,
+		Args: `[{"a\"b","c\\d",NULL}]`,
+	},
+	{
+		Name: "neq array",
+		E:    Neq(C("id"), Array([]int{1, 2})),
+		D:    PostgreSQL,
+		SQL:  `"id" != ALL ($1)`,
+		Args: "[{1,2}]",
+	},
+	{
+		Name: "emulated eq array",
+		E:    Eq(C("id"), Array([]int{1, 2})),
+		D:    MySQL,
+		SQL:  "`id` IN (?,?)",
+		Args: "[1 2]",
+	},
+	{
+		Name: "emulated any array",
+		E:    Gt(C("id"), Any(Array([]int{1, 2}))),
+		D:    SQLite,
+		SQL:  `("id" > ?)OR("id" > ?)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "any expression",
+		E:    Gte(C("id"), Any(C("ids"))),
+		D:    PostgreSQL,
+		SQL:  `"id" >= ANY ("ids")`,
+		Args: "[]",
+	},
+	{
+		Name: "emulated any as in",
+		E:    Eq(C("id"), Any([]int{1, 2, 3})),
+		D:    MySQL,
+		SQL:  "`id` IN (?,?,?)",
+		Args: "[1 2 3]",
+	},
+	{
+		Name: "emulated all as not in",
+		E:    Neq(C("id"), All([]int{1, 2})),
+		D:    SQLite,
+		SQL:  `"id" NOT IN (?,?)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "emulated any",
+		E:    Gt(C("age"), Any([]int{10, 20})),
+		D:    SQLite,
+		SQL:  `("age" > ?)OR("age" > ?)`,
+		Args: "[10 20]",
+	},
+	{
+		Name: "emulated all",
+		E:    Lt(C("age"), All([]int{10, 20})),
+		D:    MySQL,
+		SQL:  "(`age` < ?)AND(`age` < ?)",
+		Args: "[10 20]",
+	},
+	{
+		Name: "emulated empty any",
+		E:    Eq(C("id"), Any([]int{})),
+		D:    SQLite,
+		SQL:  `1=0`,
+		Args: "[]",
+	},
+	{
+		Name: "between",
+		E:    Between(C("age"), 10, Add(C("min_age"), 10)),
+		D:    PostgreSQL,
+		SQL:  `"age" BETWEEN $1 AND "min_age" + $2`,
+		Args: "[10 10]",
+	},
+	{
+		Name: "not between",
+		E:    NotBetween(C("age"), 10, 20),
+		D:    MySQL,
+		SQL:  "`age` NOT BETWEEN ? AND ?",
+		Args: "[10 20]",
+	},
+	{
+		Name: "null-safe eq postgresql",
+		E:    NullSafeEq(C("a"), (*int)(nil)),
+		D:    PostgreSQL,
+		SQL:  `"a" IS NOT DISTINCT FROM $1`,
+		Args: "[<nil>]",
+	},
+	{
+		Name: "null-safe neq postgresql",
+		E:    NullSafeNeq(C("a"), C("b")),
+		D:    PostgreSQL,
+		SQL:  `"a" IS DISTINCT FROM "b"`,
+		Args: "[]",
+	},
+	{
+		Name: "null-safe eq mysql",
+		E:    NullSafeEq(C("a"), 1),
+		D:    MySQL,
+		SQL:  "`a` <=> ?",
+		Args: "[1]",
+	},
+	{
+		Name: "null-safe neq mysql",
+		E:    NullSafeNeq(C("a"), 1),
+		D:    MySQL,
+		SQL:  "NOT (`a` <=> ?)",
+		Args: "[1]",
+	},
+	{
+		Name: "null-safe eq sqlite",
+		E:    NullSafeEq(C("a"), 1),
+		D:    SQLite,
+		SQL:  `"a" IS ?`,
+		Args: "[1]",
+	},
+	{
+		Name: "null-safe neq sqlite",
+		E:    NullSafeNeq(C("a"), 1),
+		D:    SQLite,
+		SQL:  `"a" IS NOT ?`,
+		Args: "[1]",
+	},
+	{
+		Name: "null-safe eq with nil",
+		E:    NullSafeEq(nil, C("a")),
+		D:    MySQL,
+		SQL:  "`a` IS NULL",
+		Args: "[]",
+	},
+	{
+		Name: "null-safe neq with nil",
+		E:    NullSafeNeq(C("a"), nil),
+		D:    MySQL,
+		SQL:  "`a` IS NOT NULL",
+		Args: "[]",
+	},
+	{
+		Name: "emulated empty all",
+		E:    Gt(C("id"), All([]int{})),
+		D:    MySQL,
+		SQL:  `TRUE`,
+		Args: "[]",
+	},
+}
+
+func TestPredicate(t *testing.T) {
+	for i, test := range predicateTests {
+		buf, ctx := qutil.NewContext(test.E, 32, 1, test.D)
+		buf = test.E.WriteExpression(ctx, buf)
+		if got := string(buf); got != test.SQL {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.SQL, got)
+		}
+		if got := fmt.Sprint(ctx.Args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+	}
+}
+
+func TestPredicateInBuilder(t *testing.T) {
+	user, post := T("user", "u"), T("post", "p")
+	sel := Select().From(user).Where(
+		NotExists(Select().Column(Unsafe("1").C()).From(post).Where(Eq(post.C("user_id"), user.C("id")))),
+		Not(Eq(user.C("id"), Any(Array([]int{1, 2})))),
+	).SetDialect(PostgreSQL)
+	sql, args := sel.ToSQL()
+	if want := `SELECT * FROM "user" AS "u" WHERE (NOT EXISTS (SELECT 1 FROM "post" AS "p" WHERE "p"."user_id" = "u"."id"))AND(NOT ("u"."id" = ANY ($1)))`; sql != want {
+		t.Errorf("want %s got %s", want, sql)
+	}
+	if want, got := `[{1,2}]`, fmt.Sprint(args); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	r := Transform(sel, func(n interface{}) interface{} {
+		if c, ok := n.(Column); ok && fmt.Sprint(c) == `"p"."user_id" []` {
+			return post.C("author_id")
+		}
+		return n
+	}).(*ZSelectBuilder)
+	if want, got := `SELECT * FROM "user" AS "u" WHERE (NOT EXISTS (SELECT 1 FROM "post" AS "p" WHERE "p"."author_id" = "u"."id"))AND(NOT ("u"."id" = ANY ($1))) [{1,2}]`, r.String(); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestArrayPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	Array(1)
+}
+
+func TestArrayValue(t *testing.T) {
+	now := time.Date(2015, 12, 12, 20, 19, 18, 0, time.UTC)
+	for i, test := range []struct {
+		V    interface{}
+		Want interface{}
+		Err  bool
+	}{
+		{V: []int(nil), Want: nil},
+		{V: []int{}, Want: "{}"},
+		{V: []uint8{1, 2}, Want: "{1,2}"},
+		{V: []float64{1.5, -2}, Want: "{1.5,-2}"},
+		{V: []bool{true, false}, Want: "{true,false}"},
+		{V: []string{"a b", `"`, `\`, "NULL"}, Want: `{"a b","\"","\\","NULL"}`},
+		{V: []time.Time{now}, Want: `{"2015-12-12T20:19:18Z"}`},
+		{V: []interface{}{sql.NullInt64{}, sql.NullInt64{Int64: 1, Valid: true}}, Want: "{NULL,1}"},
+		{V: []struct{}{{}}, Err: true},
+		{V: [][]int{{1}}, Err: true},
+	} {
+		v, err := arrayValue{V: test.V}.Value()
+		if (err != nil) != test.Err {
+			t.Errorf("tests[%d] want error %v got %v", i, test.Err, err)
+			continue
+		}
+		if v != test.Want {
+			t.Errorf("tests[%d] want %v got %v", i, test.Want, v)
+		}
+	}
+}
+
+func TestQuantifiedOnDB(t *testing.T) {
+	for _, testData := range testModel {
+		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
+			defer exec(t, "drops", db, d, testData.drops)
+			exec(t, "drops", db, d, testData.drops)
+			exec(t, "creates", db, d, testData.creates)
+			exec(t, "inserts", db, d, testData.inserts)
+			post := T("post")
+			for i, test := range []struct {
+				Name string
+				E    Expression
+				Want int64
+			}{
+				{"any slice", Eq(post.C("id"), Any([]int{1, 2, 3})), 3},
+				{"any array", Eq(post.C("id"), Any(Array([]int{1, 2, 3}))), 3},
+				{"eq array", Eq(post.C("id"), Array([]int64{2, 4})), 2},
+				{"all array", Neq(post.C("id"), All(Array([]int{1, 2, 3}))), 1},
+				{"gt any array", Gt(post.C("id"), Any(Array([]int{2, 3}))), 2},
+				{"empty array", Eq(post.C("id"), Any(Array([]int{}))), 0},
+			} {
+				var r int64
+				sql, args := Select().SetDialect(d).Column(CountAll().C()).From(post).Where(test.E).ToSQL()
+				if err := db.QueryRow(sql, args...).Scan(&r); err != nil {
+					t.Fatalf("%s tests[%d] %s Error: %v\n%s", d, i, test.Name, err, sql)
+				}
+				if r != test.Want {
+					t.Errorf("%s tests[%d] %s want %d got %d", d, i, test.Name, test.Want, r)
+				}
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package qslog implements q.Hook which writes the statements to log/slog.
+//
+// It is separated from package q because log/slog requires Go 1.21.
+package qslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/oov/q"
+)
+
+type hook struct {
+	Logger *slog.Logger
+}
+
+func (h *hook) Before(ctx context.Context, e *q.HookEvent) context.Context { return ctx }
+func (h *hook) After(ctx context.Context, e *q.HookEvent) {
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("kind", e.Kind),
+		slog.String("sql", e.SQL),
+		slog.Any("args", e.Args),
+		slog.Duration("duration", e.Duration),
+		slog.Int64("rows_affected", e.RowsAffected),
+	}
+	if e.Err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", e.Err))
+	}
+	h.Logger.LogAttrs(ctx, level, "q: execute", attrs...)
+}
+
+// Hook creates q.Hook which writes each statement to l.
+// Statements are logged at debug level, or at error level when they fail.
+func Hook(l *slog.Logger) q.Hook {
+	return &hook{Logger: l}
+}
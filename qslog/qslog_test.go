@@ -0,0 +1,39 @@
+package qslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/oov/q"
+)
+
+func TestHook(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	h := Hook(l)
+	for _, e := range []*q.HookEvent{
+		{Kind: "INSERT", SQL: `INSERT INTO "user"("name") VALUES (?)`, Args: []interface{}{"a"}, RowsAffected: 3},
+		{Kind: "DELETE", SQL: `DELETE FROM "error"`, Args: []interface{}{}, RowsAffected: -1, Err: errors.New("fake error")},
+	} {
+		ctx := h.Before(context.Background(), e)
+		h.After(ctx, e)
+	}
+
+	want := `level=DEBUG msg="q: execute" kind=INSERT sql="INSERT INTO \"user\"(\"name\") VALUES (?)" args=[a] rows_affected=3
+level=ERROR msg="q: execute" kind=DELETE sql="DELETE FROM \"error\"" args=[] rows_affected=-1 error="fake error"
+`
+	if got := buf.String(); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
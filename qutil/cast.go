@@ -0,0 +1,151 @@
+package qutil
+
+type TypeKind int
+
+const (
+	Integer = TypeKind(iota)
+	BigInt
+	Decimal
+	Text
+	Varchar
+	Boolean
+	Date
+	Timestamp
+	JSON
+	UUID
+	Bytes
+)
+
+// Type represents the portable type which is mapped to the native type in each dialect.
+type Type struct {
+	Kind  TypeKind
+	Size  int // The length of Varchar or the precision of Decimal. 0 means unspecified.
+	Scale int // The scale of Decimal.
+}
+
+func writeCast(ctx *Context, buf []byte, v interface{}, name string) []byte {
+	buf = append(buf, "CAST("...)
+	buf = writeIntf(v, ctx, buf)
+	buf = append(buf, " AS "...)
+	buf = append(buf, name...)
+	return append(buf, ')')
+}
+
+// writeSize writes the name of the type with the size such as "VARCHAR(n)" or "DECIMAL(p, s)".
+func writeSize(buf []byte, name string, t Type) []byte {
+	buf = append(buf, name...)
+	if t.Size <= 0 {
+		return buf
+	}
+	buf = append(buf, '(')
+	buf = writeInt(buf, t.Size)
+	if t.Kind == Decimal {
+		buf = append(buf, ", "...)
+		buf = writeInt(buf, t.Scale)
+	}
+	return append(buf, ')')
+}
+
+// writeNonZero writes "(v != 0)" for the dialects which have no boolean type.
+func writeNonZero(ctx *Context, buf []byte, v interface{}) []byte {
+	buf = append(buf, '(')
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, " != 0)"...)
+}
+
+// standardTypeName returns the name of t in standard SQL.
+func standardTypeName(t Type, decimal, bytes string) string {
+	switch t.Kind {
+	case Integer:
+		return "INTEGER"
+	case BigInt:
+		return "BIGINT"
+	case Decimal:
+		return string(writeSize(nil, decimal, t))
+	case Text:
+		return "TEXT"
+	case Varchar:
+		return string(writeSize(nil, "VARCHAR", t))
+	case Boolean:
+		return "BOOLEAN"
+	case Date:
+		return "DATE"
+	case Timestamp:
+		return "TIMESTAMP"
+	case JSON:
+		return "JSON"
+	case UUID:
+		return "UUID"
+	case Bytes:
+		return bytes
+	}
+	panic("qutil: unknown type kind")
+}
+
+// Cast writes CAST with the types which can be used in CAST in MySQL,
+// so the integers are SIGNED and the strings are CHAR.
+// MySQL has no boolean type, so the cast to Boolean is written as "(v != 0)".
+func (mySQL) Cast(ctx *Context, buf []byte, v interface{}, t Type) []byte {
+	var name string
+	switch t.Kind {
+	case Integer, BigInt:
+		name = "SIGNED"
+	case Decimal:
+		name = string(writeSize(nil, "DECIMAL", t))
+	case Text:
+		name = "CHAR"
+	case Varchar:
+		name = string(writeSize(nil, "CHAR", t))
+	case Boolean:
+		return writeNonZero(ctx, buf, v)
+	case Timestamp:
+		name = "DATETIME"
+	case UUID:
+		name = "CHAR(36)"
+	case Bytes:
+		name = "BINARY"
+	default:
+		name = standardTypeName(t, "DECIMAL", "BINARY")
+	}
+	return writeCast(ctx, buf, v, name)
+}
+
+func (postgreSQL) Cast(ctx *Context, buf []byte, v interface{}, t Type) []byte {
+	return writeCast(ctx, buf, v, standardTypeName(t, "NUMERIC", "BYTEA"))
+}
+
+// Cast writes CAST with the type affinities in SQLite.
+// SQLite has no date and time types, so the casts to them are written by the date and time functions.
+func (sqlite) Cast(ctx *Context, buf []byte, v interface{}, t Type) []byte {
+	var name string
+	switch t.Kind {
+	case Integer, BigInt:
+		name = "INTEGER"
+	case Decimal:
+		name = "NUMERIC"
+	case Text, Varchar, UUID:
+		name = "TEXT"
+	case Boolean:
+		return writeNonZero(ctx, buf, v)
+	case Date, Timestamp, JSON:
+		switch t.Kind {
+		case Date:
+			buf = append(buf, "DATE("...)
+		case Timestamp:
+			buf = append(buf, "DATETIME("...)
+		default:
+			buf = append(buf, "JSON("...)
+		}
+		buf = writeIntf(v, ctx, buf)
+		return append(buf, ')')
+	case Bytes:
+		name = "BLOB"
+	default:
+		name = standardTypeName(t, "NUMERIC", "BLOB")
+	}
+	return writeCast(ctx, buf, v, name)
+}
+
+func (fakeDialect) Cast(ctx *Context, buf []byte, v interface{}, t Type) []byte {
+	return writeCast(ctx, buf, v, standardTypeName(t, "DECIMAL", "BLOB"))
+}
@@ -0,0 +1,39 @@
+package qutil
+
+func writeIf(ctx *Context, buf []byte, name string, cond, then, els interface{}) []byte {
+	buf = append(buf, name...)
+	buf = append(buf, '(')
+	buf = writeIntf(cond, ctx, buf)
+	buf = append(buf, ", "...)
+	buf = writeIntf(then, ctx, buf)
+	buf = append(buf, ", "...)
+	buf = writeIntf(els, ctx, buf)
+	return append(buf, ')')
+}
+
+func writeCaseWhen(ctx *Context, buf []byte, cond, then, els interface{}) []byte {
+	buf = append(buf, "CASE WHEN "...)
+	buf = writeIntf(cond, ctx, buf)
+	buf = append(buf, " THEN "...)
+	buf = writeIntf(then, ctx, buf)
+	buf = append(buf, " ELSE "...)
+	buf = writeIntf(els, ctx, buf)
+	return append(buf, " END"...)
+}
+
+func (mySQL) If(ctx *Context, buf []byte, cond, then, els interface{}) []byte {
+	return writeIf(ctx, buf, "IF", cond, then, els)
+}
+
+func (postgreSQL) If(ctx *Context, buf []byte, cond, then, els interface{}) []byte {
+	return writeCaseWhen(ctx, buf, cond, then, els)
+}
+
+// If writes IIF which is available in SQLite 3.32.0 or later.
+func (sqlite) If(ctx *Context, buf []byte, cond, then, els interface{}) []byte {
+	return writeIf(ctx, buf, "IIF", cond, then, els)
+}
+
+func (fakeDialect) If(ctx *Context, buf []byte, cond, then, els interface{}) []byte {
+	return writeCaseWhen(ctx, buf, cond, then, els)
+}
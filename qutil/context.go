@@ -3,10 +3,14 @@ package qutil
 type Context struct {
 	Starter     interface{}
 	CUD         bool // Whether current context is Create or Update or Delete.
+	Normalize   bool // Whether variable-length lists should be written in a normalized form.
+	Format      bool // Whether SQL should be written in multiple lines for humans.
+	Indent      int  // Current indent level which is used when Format is true.
 	Dialect     Dialect
 	Placeholder Placeholder
 	Args        []interface{}
 	ArgsMap     map[interface{}]int
+	Err         error // The first error which occurred while writing SQL.
 }
 
 func NewContext(starter interface{}, bufCap int, argsCap int, d Dialect) ([]byte, *Context) {
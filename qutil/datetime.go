@@ -0,0 +1,356 @@
+package qutil
+
+import "errors"
+
+func unsupportedUnit(u IntervalUnit) error {
+	return errors.New("q: unsupported interval unit type: " + string(writeInt(nil, int(u))))
+}
+
+// setError records err to ctx if no error has occurred yet.
+func setError(ctx *Context, err error) {
+	if ctx.Err == nil {
+		ctx.Err = err
+	}
+}
+
+// checkUnit reports false and records the error to ctx
+// if u can not be used in DateTrunc, Extract and DateDiff such as Millisecond.
+// The caller should write NULL instead of the function then.
+func checkUnit(ctx *Context, u IntervalUnit) bool {
+	if u >= Year && u <= Week {
+		return true
+	}
+	setError(ctx, unsupportedUnit(u))
+	return false
+}
+
+// unitName returns the name of u in upper case such as "DAY".
+// It returns an empty string if u is not accepted by checkUnit.
+func unitName(u IntervalUnit) string {
+	switch u {
+	case Year:
+		return "YEAR"
+	case Month:
+		return "MONTH"
+	case Week:
+		return "WEEK"
+	case Day:
+		return "DAY"
+	case Hour:
+		return "HOUR"
+	case Minute:
+		return "MINUTE"
+	case Second:
+		return "SECOND"
+	}
+	return ""
+}
+
+// unitSeconds returns the number of seconds in u.
+// It returns 0 if u has no fixed length such as Year and Month.
+func unitSeconds(u IntervalUnit) int {
+	switch u {
+	case Week:
+		return 7 * 24 * 60 * 60
+	case Day:
+		return 24 * 60 * 60
+	case Hour:
+		return 60 * 60
+	case Minute:
+		return 60
+	case Second:
+		return 1
+	}
+	return 0
+}
+
+// raw represents the fragment of SQL which is written as it is by writeFunc.
+type raw string
+
+// writeFunc writes the function call such as "name(vs[0], vs[1])".
+// The raw values in vs are written as they are, and the others are written as writeIntf does.
+func writeFunc(ctx *Context, buf []byte, name string, vs ...interface{}) []byte {
+	buf = append(buf, name...)
+	buf = append(buf, '(')
+	for i, v := range vs {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		if s, ok := v.(raw); ok {
+			buf = append(buf, s...)
+		} else {
+			buf = writeIntf(v, ctx, buf)
+		}
+	}
+	return append(buf, ')')
+}
+
+func writeExtract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	buf = append(buf, "EXTRACT("...)
+	buf = append(buf, unitName(u)...)
+	buf = append(buf, " FROM "...)
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, ')')
+}
+
+func writeTimestampDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	return writeFunc(ctx, buf, "TIMESTAMPDIFF", raw(unitName(u)), from, to)
+}
+
+// DateTrunc writes DATE_FORMAT which replaces the smaller parts with zero,
+// and the result is converted into DATETIME.
+// The weeks start on Monday.
+func (mySQL) DateTrunc(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	buf = append(buf, "CAST("...)
+	switch u {
+	case Week:
+		buf = append(buf, "DATE("...)
+		buf = writeIntf(v, ctx, buf)
+		buf = append(buf, ") - INTERVAL WEEKDAY("...)
+		buf = writeIntf(v, ctx, buf)
+		buf = append(buf, ") DAY"...)
+	default:
+		var f string
+		switch u {
+		case Year:
+			f = "'%Y-01-01 00:00:00'"
+		case Month:
+			f = "'%Y-%m-01 00:00:00'"
+		case Day:
+			f = "'%Y-%m-%d 00:00:00'"
+		case Hour:
+			f = "'%Y-%m-%d %H:00:00'"
+		case Minute:
+			f = "'%Y-%m-%d %H:%i:00'"
+		case Second:
+			f = "'%Y-%m-%d %H:%i:%s'"
+		}
+		buf = writeFunc(ctx, buf, "DATE_FORMAT", v, raw(f))
+	}
+	return append(buf, " AS DATETIME)"...)
+}
+
+// Extract writes EXTRACT, but Week is written as WEEK(v, 3) which returns the ISO 8601 week number.
+func (mySQL) Extract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if u == Week {
+		return writeFunc(ctx, buf, "WEEK", v, raw("3"))
+	}
+	return writeExtract(ctx, buf, u, v)
+}
+
+func (mySQL) DateDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte {
+	return writeTimestampDiff(ctx, buf, u, from, to)
+}
+
+func (mySQL) FormatISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "DATE_FORMAT", v, raw("'%Y-%m-%dT%H:%i:%s'"))
+}
+
+func (mySQL) ParseISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "STR_TO_DATE", v, raw("'%Y-%m-%dT%H:%i:%s'"))
+}
+
+func (postgreSQL) DateTrunc(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	buf = append(buf, "DATE_TRUNC('"...)
+	buf = append(buf, unitName(u)...)
+	buf = append(buf, "', "...)
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, ')')
+}
+
+func (postgreSQL) Extract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	return writeExtract(ctx, buf, u, v)
+}
+
+// DateDiff writes AGE for Year and Month to count the calendar months,
+// and writes the difference of EPOCH for the other units.
+// The result is truncated toward zero like TIMESTAMPDIFF in MySQL.
+func (postgreSQL) DateDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	buf = append(buf, "CAST("...)
+	switch u {
+	case Year:
+		buf = append(buf, "EXTRACT(YEAR FROM "...)
+		buf = writeFunc(ctx, buf, "AGE", to, from)
+		buf = append(buf, ')')
+	case Month:
+		buf = append(buf, "EXTRACT(YEAR FROM "...)
+		buf = writeFunc(ctx, buf, "AGE", to, from)
+		buf = append(buf, ") * 12 + EXTRACT(MONTH FROM "...)
+		buf = writeFunc(ctx, buf, "AGE", to, from)
+		buf = append(buf, ')')
+	default:
+		s := unitSeconds(u)
+		buf = append(buf, "TRUNC(EXTRACT(EPOCH FROM "...)
+		buf = writeIntf(to, ctx, buf)
+		buf = append(buf, " - "...)
+		buf = writeIntf(from, ctx, buf)
+		buf = append(buf, ')')
+		if s != 1 {
+			buf = append(buf, " / "...)
+			buf = writeInt(buf, s)
+		}
+		buf = append(buf, ')')
+	}
+	return append(buf, " AS BIGINT)"...)
+}
+
+func (postgreSQL) FormatISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "TO_CHAR", v, raw(`'YYYY-MM-DD"T"HH24:MI:SS'`))
+}
+
+func (postgreSQL) ParseISO(ctx *Context, buf []byte, v interface{}) []byte {
+	buf = append(buf, "CAST("...)
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, " AS TIMESTAMP)"...)
+}
+
+// DateTrunc writes DATETIME with the modifiers or STRFTIME.
+// The weeks start on Monday.
+func (sqlite) DateTrunc(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	switch u {
+	case Year:
+		return writeFunc(ctx, buf, "DATETIME", v, raw("'start of year'"))
+	case Month:
+		return writeFunc(ctx, buf, "DATETIME", v, raw("'start of month'"))
+	case Week:
+		return writeFunc(ctx, buf, "DATETIME", v, raw("'start of day'"), raw("'-6 days'"), raw("'weekday 1'"))
+	case Day:
+		return writeFunc(ctx, buf, "DATETIME", v, raw("'start of day'"))
+	case Hour:
+		return writeFunc(ctx, buf, "STRFTIME", raw("'%Y-%m-%d %H:00:00'"), v)
+	case Minute:
+		return writeFunc(ctx, buf, "STRFTIME", raw("'%Y-%m-%d %H:%M:00'"), v)
+	}
+	return writeFunc(ctx, buf, "DATETIME", v)
+}
+
+// Extract writes STRFTIME which is converted into INTEGER.
+// Week is the week of the year which starts on the first Monday (00-53), not ISO 8601.
+func (sqlite) Extract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	var f string
+	switch u {
+	case Year:
+		f = "'%Y'"
+	case Month:
+		f = "'%m'"
+	case Week:
+		f = "'%W'"
+	case Day:
+		f = "'%d'"
+	case Hour:
+		f = "'%H'"
+	case Minute:
+		f = "'%M'"
+	case Second:
+		f = "'%S'"
+	}
+	buf = append(buf, "CAST("...)
+	buf = writeFunc(ctx, buf, "STRFTIME", raw(f), v)
+	return append(buf, " AS INTEGER)"...)
+}
+
+// DateDiff writes the calculation with STRFTIME.
+// Year and Month count the calendar months like TIMESTAMPDIFF in MySQL,
+// and the other units are calculated from the difference of the unix times to avoid the rounding error of JULIANDAY.
+func (sqlite) DateDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte {
+	if !checkUnit(ctx, u) {
+		return append(buf, "NULL"...)
+	}
+	if u != Year && u != Month {
+		buf = append(buf, "((STRFTIME('%s', "...)
+		buf = writeIntf(to, ctx, buf)
+		buf = append(buf, ") - STRFTIME('%s', "...)
+		buf = writeIntf(from, ctx, buf)
+		buf = append(buf, ")) / "...)
+		buf = writeInt(buf, unitSeconds(u))
+		return append(buf, ')')
+	}
+
+	part := func(f string, v interface{}) {
+		buf = append(buf, "STRFTIME('"...)
+		buf = append(buf, f...)
+		buf = append(buf, "', "...)
+		buf = writeIntf(v, ctx, buf)
+		buf = append(buf, ')')
+	}
+	// The number of months is decreased if the rest of "to" is smaller than "from",
+	// and is increased on the contrary when "to" is before "from".
+	buf = append(buf, "((("...)
+	part("%Y", to)
+	buf = append(buf, " - "...)
+	part("%Y", from)
+	buf = append(buf, ") * 12 + "...)
+	part("%m", to)
+	buf = append(buf, " - "...)
+	part("%m", from)
+	buf = append(buf, " - ("...)
+	part("%Y-%m-%d %H:%M:%S", to)
+	buf = append(buf, " >= "...)
+	part("%Y-%m-%d %H:%M:%S", from)
+	buf = append(buf, " AND "...)
+	part("%d %H:%M:%S", to)
+	buf = append(buf, " < "...)
+	part("%d %H:%M:%S", from)
+	buf = append(buf, ") + ("...)
+	part("%Y-%m-%d %H:%M:%S", to)
+	buf = append(buf, " < "...)
+	part("%Y-%m-%d %H:%M:%S", from)
+	buf = append(buf, " AND "...)
+	part("%d %H:%M:%S", to)
+	buf = append(buf, " > "...)
+	part("%d %H:%M:%S", from)
+	buf = append(buf, "))"...)
+	if u == Year {
+		buf = append(buf, " / 12"...)
+	}
+	return append(buf, ')')
+}
+
+func (sqlite) FormatISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "STRFTIME", raw("'%Y-%m-%dT%H:%M:%S'"), v)
+}
+
+func (sqlite) ParseISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "DATETIME", v)
+}
+
+func (fakeDialect) DateTrunc(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	return postgreSQL{}.DateTrunc(ctx, buf, u, v)
+}
+
+func (fakeDialect) Extract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte {
+	return writeExtract(ctx, buf, u, v)
+}
+
+func (fakeDialect) DateDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte {
+	return writeTimestampDiff(ctx, buf, u, from, to)
+}
+
+func (fakeDialect) FormatISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return postgreSQL{}.FormatISO(ctx, buf, v)
+}
+
+func (fakeDialect) ParseISO(ctx *Context, buf []byte, v interface{}) []byte {
+	return postgreSQL{}.ParseISO(ctx, buf, v)
+}
@@ -5,11 +5,43 @@ type IntervalUnit int
 type Dialect interface {
 	Placeholder() Placeholder
 	Quote(buf []byte, word string) []byte
+	QuoteString(buf []byte, s string) []byte
 	CanUseReturning() bool
 	CanUseInnerJoinWithoutCondition() bool
 	CanUseLeftJoinWithoutCondition() bool
 	CharLengthName() string
+	SubstringName() string
+	PositionName() string
+	GreatestName() string
+	LeastName() string
 	AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte
+	CurrentDate() string
+	CurrentTime() string
+	DateTrunc(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte
+	Extract(ctx *Context, buf []byte, u IntervalUnit, v interface{}) []byte
+	DateDiff(ctx *Context, buf []byte, u IntervalUnit, from, to interface{}) []byte
+	FormatISO(ctx *Context, buf []byte, v interface{}) []byte
+	ParseISO(ctx *Context, buf []byte, v interface{}) []byte
+	BoolLiteral(v bool) string
+	CanUseArrayParameter() bool
+	CanUseRowValue() bool
+	NullIsSmallest() bool
+	CanUseDistinctOn() bool
+	CanUseGroupingSets() bool
+	CanUseWithRollup() bool
+	CanUseNullsOrder() bool
+	ILike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte
+	Concat(ctx *Context, buf []byte, vs []interface{}) []byte
+	BitXor(ctx *Context, buf []byte, l, r interface{}) []byte
+	NullSafeEq(ctx *Context, buf []byte, l, r interface{}, not bool) []byte
+	Cast(ctx *Context, buf []byte, v interface{}, t Type) []byte
+	If(ctx *Context, buf []byte, cond, then, els interface{}) []byte
+	ByteLength(ctx *Context, buf []byte, v interface{}) []byte
+	Left(ctx *Context, buf []byte, v, n interface{}) []byte
+	Right(ctx *Context, buf []byte, v, n interface{}) []byte
+	LPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte
+	RPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte
+	Repeat(ctx *Context, buf []byte, v, n interface{}) []byte
 }
 
 type Placeholder interface {
@@ -32,6 +64,23 @@ func escape(buf []byte, q byte, word string) []byte {
 	return buf
 }
 
+// escapeBackslash quotes s as the string literal in MySQL,
+// which also escapes the backslashes.
+func escapeBackslash(buf []byte, s string) []byte {
+	buf = append(buf, '\'')
+	for _, c := range []byte(s) {
+		switch c {
+		case '\'':
+			buf = append(buf, '\'', '\'')
+		case '\\':
+			buf = append(buf, '\\', '\\')
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '\'')
+}
+
 var (
 	// MySQL implements a dialect in MySQL.
 	MySQL = Dialect(mySQL{})
@@ -43,44 +92,111 @@ var (
 
 type mySQL struct{}
 
-func (mySQL) String() string                        { return "MySQL" }
-func (mySQL) Placeholder() Placeholder              { return genericPlaceholder{} }
-func (mySQL) Quote(buf []byte, word string) []byte  { return escape(buf, '`', word) }
-func (mySQL) CanUseReturning() bool                 { return false }
-func (mySQL) CanUseInnerJoinWithoutCondition() bool { return true }
-func (mySQL) CanUseLeftJoinWithoutCondition() bool  { return false }
-func (mySQL) CharLengthName() string                { return "CHAR_LENGTH" }
+func (mySQL) String() string                          { return "MySQL" }
+func (mySQL) Placeholder() Placeholder                { return genericPlaceholder{} }
+func (mySQL) Quote(buf []byte, word string) []byte    { return escape(buf, '`', word) }
+func (mySQL) QuoteString(buf []byte, s string) []byte { return escapeBackslash(buf, s) }
+func (mySQL) CanUseReturning() bool                   { return false }
+func (mySQL) CanUseInnerJoinWithoutCondition() bool   { return true }
+func (mySQL) CanUseLeftJoinWithoutCondition() bool    { return false }
+func (mySQL) CharLengthName() string                  { return "CHAR_LENGTH" }
+func (mySQL) SubstringName() string                   { return "SUBSTRING" }
+func (mySQL) PositionName() string                    { return "INSTR" }
+func (mySQL) GreatestName() string                    { return "GREATEST" }
+func (mySQL) LeastName() string                       { return "LEAST" }
+func (mySQL) CurrentDate() string                     { return "CURRENT_DATE" }
+func (mySQL) CurrentTime() string                     { return "CURRENT_TIME" }
+func (mySQL) BoolLiteral(v bool) string               { return boolLiteral(v, "TRUE", "FALSE") }
+func (mySQL) CanUseArrayParameter() bool              { return false }
+func (mySQL) CanUseRowValue() bool                    { return true }
+func (mySQL) NullIsSmallest() bool                    { return true }
+func (mySQL) CanUseDistinctOn() bool                  { return false }
+func (mySQL) CanUseGroupingSets() bool                { return false }
+func (mySQL) CanUseWithRollup() bool                  { return true }
+func (mySQL) CanUseNullsOrder() bool                  { return false }
 
 type postgreSQL struct{}
 
-func (postgreSQL) String() string                        { return "PostgreSQL" }
-func (postgreSQL) Placeholder() Placeholder              { return &postgresPlaceholder{} }
-func (postgreSQL) Quote(buf []byte, word string) []byte  { return escape(buf, '"', word) }
-func (postgreSQL) CanUseReturning() bool                 { return true }
-func (postgreSQL) CanUseInnerJoinWithoutCondition() bool { return false }
-func (postgreSQL) CanUseLeftJoinWithoutCondition() bool  { return false }
-func (postgreSQL) CharLengthName() string                { return "CHAR_LENGTH" }
+func (postgreSQL) String() string                          { return "PostgreSQL" }
+func (postgreSQL) Placeholder() Placeholder                { return &postgresPlaceholder{} }
+func (postgreSQL) Quote(buf []byte, word string) []byte    { return escape(buf, '"', word) }
+func (postgreSQL) QuoteString(buf []byte, s string) []byte { return escape(buf, '\'', s) }
+func (postgreSQL) CanUseReturning() bool                   { return true }
+func (postgreSQL) CanUseInnerJoinWithoutCondition() bool   { return false }
+func (postgreSQL) CanUseLeftJoinWithoutCondition() bool    { return false }
+func (postgreSQL) CharLengthName() string                  { return "CHAR_LENGTH" }
+func (postgreSQL) SubstringName() string                   { return "SUBSTR" }
+func (postgreSQL) PositionName() string                    { return "STRPOS" }
+func (postgreSQL) GreatestName() string                    { return "GREATEST" }
+func (postgreSQL) LeastName() string                       { return "LEAST" }
+func (postgreSQL) CurrentDate() string                     { return "CURRENT_DATE" }
+func (postgreSQL) CurrentTime() string                     { return "LOCALTIME" }
+func (postgreSQL) BoolLiteral(v bool) string               { return boolLiteral(v, "TRUE", "FALSE") }
+func (postgreSQL) CanUseArrayParameter() bool              { return true }
+func (postgreSQL) CanUseRowValue() bool                    { return true }
+func (postgreSQL) NullIsSmallest() bool                    { return false }
+func (postgreSQL) CanUseDistinctOn() bool                  { return true }
+func (postgreSQL) CanUseGroupingSets() bool                { return true }
+func (postgreSQL) CanUseWithRollup() bool                  { return false }
+func (postgreSQL) CanUseNullsOrder() bool                  { return true }
 
 type sqlite struct{}
 
-func (sqlite) String() string                        { return "SQLite" }
-func (sqlite) Placeholder() Placeholder              { return genericPlaceholder{} }
-func (sqlite) Quote(buf []byte, word string) []byte  { return escape(buf, '"', word) }
-func (sqlite) CanUseReturning() bool                 { return false }
-func (sqlite) CanUseInnerJoinWithoutCondition() bool { return true }
-func (sqlite) CanUseLeftJoinWithoutCondition() bool  { return true }
-func (sqlite) CharLengthName() string                { return "LENGTH" }
+func (sqlite) String() string                          { return "SQLite" }
+func (sqlite) Placeholder() Placeholder                { return genericPlaceholder{} }
+func (sqlite) Quote(buf []byte, word string) []byte    { return escape(buf, '"', word) }
+func (sqlite) QuoteString(buf []byte, s string) []byte { return escape(buf, '\'', s) }
+func (sqlite) CanUseReturning() bool                   { return false }
+func (sqlite) CanUseInnerJoinWithoutCondition() bool   { return true }
+func (sqlite) CanUseLeftJoinWithoutCondition() bool    { return true }
+func (sqlite) CharLengthName() string                  { return "LENGTH" }
+func (sqlite) SubstringName() string                   { return "SUBSTR" }
+func (sqlite) PositionName() string                    { return "INSTR" }
+func (sqlite) GreatestName() string                    { return "MAX" }
+func (sqlite) LeastName() string                       { return "MIN" }
+func (sqlite) CurrentDate() string                     { return "CURRENT_DATE" }
+func (sqlite) CurrentTime() string                     { return "CURRENT_TIME" }
+func (sqlite) BoolLiteral(v bool) string               { return boolLiteral(v, "1=1", "1=0") }
+func (sqlite) CanUseArrayParameter() bool              { return false }
+func (sqlite) CanUseRowValue() bool                    { return false }
+func (sqlite) NullIsSmallest() bool                    { return true }
+func (sqlite) CanUseDistinctOn() bool                  { return false }
+func (sqlite) CanUseGroupingSets() bool                { return false }
+func (sqlite) CanUseWithRollup() bool                  { return false }
+func (sqlite) CanUseNullsOrder() bool                  { return true }
 
 type fakeDialect struct{}
 
-func (fakeDialect) String() string                        { return "FakeDialect" }
-func (fakeDialect) Placeholder() Placeholder              { return fakeDialect{} }
-func (fakeDialect) Next(buf []byte) []byte                { return append(buf, '?') }
-func (fakeDialect) Quote(buf []byte, word string) []byte  { return escape(buf, '"', word) }
-func (fakeDialect) CanUseReturning() bool                 { return true }
-func (fakeDialect) CanUseInnerJoinWithoutCondition() bool { return true }
-func (fakeDialect) CanUseLeftJoinWithoutCondition() bool  { return true }
-func (fakeDialect) CharLengthName() string                { return "CHAR_LENGTH" }
+func (fakeDialect) String() string                          { return "FakeDialect" }
+func (fakeDialect) Placeholder() Placeholder                { return fakeDialect{} }
+func (fakeDialect) Next(buf []byte) []byte                  { return append(buf, '?') }
+func (fakeDialect) Quote(buf []byte, word string) []byte    { return escape(buf, '"', word) }
+func (fakeDialect) QuoteString(buf []byte, s string) []byte { return escape(buf, '\'', s) }
+func (fakeDialect) CanUseReturning() bool                   { return true }
+func (fakeDialect) CanUseInnerJoinWithoutCondition() bool   { return true }
+func (fakeDialect) CanUseLeftJoinWithoutCondition() bool    { return true }
+func (fakeDialect) CharLengthName() string                  { return "CHAR_LENGTH" }
+func (fakeDialect) SubstringName() string                   { return "SUBSTR" }
+func (fakeDialect) PositionName() string                    { return "STRPOS" }
+func (fakeDialect) GreatestName() string                    { return "GREATEST" }
+func (fakeDialect) LeastName() string                       { return "LEAST" }
+func (fakeDialect) CurrentDate() string                     { return "CURRENT_DATE" }
+func (fakeDialect) CurrentTime() string                     { return "CURRENT_TIME" }
+func (fakeDialect) BoolLiteral(v bool) string               { return boolLiteral(v, "TRUE", "FALSE") }
+func (fakeDialect) CanUseArrayParameter() bool              { return true }
+func (fakeDialect) CanUseRowValue() bool                    { return true }
+func (fakeDialect) NullIsSmallest() bool                    { return false }
+func (fakeDialect) CanUseDistinctOn() bool                  { return true }
+func (fakeDialect) CanUseGroupingSets() bool                { return true }
+func (fakeDialect) CanUseWithRollup() bool                  { return false }
+func (fakeDialect) CanUseNullsOrder() bool                  { return true }
+
+func boolLiteral(v bool, t, f string) string {
+	if v {
+		return t
+	}
+	return f
+}
 
 type genericPlaceholder struct{}
 
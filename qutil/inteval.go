@@ -7,6 +7,9 @@ const (
 	Hour
 	Minute
 	Second
+	Week
+	Millisecond
+	Microsecond
 )
 
 type Interval interface {
@@ -14,6 +17,14 @@ type Interval interface {
 	Unit() IntervalUnit
 }
 
+// ExpressionInterval is Interval whose quantity is decided at runtime, such as a column or an argument.
+// The interval is Value() * Quantity() units, so Value is usually 1 or -1.
+// Quantity must be enclosed in parentheses if it is an operator expression.
+type ExpressionInterval interface {
+	Interval
+	Quantity() interface{}
+}
+
 type expression interface {
 	WriteExpression(ctx *Context, buf []byte) []byte
 }
@@ -45,62 +56,133 @@ func writeInt(buf []byte, x int) []byte {
 	return append(buf, b[i:]...)
 }
 
-func (mySQL) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte {
-	var v int
-	buf = writeIntf(l, ctx, buf)
+// intervalQuantity returns the quantity of iv if iv is ExpressionInterval.
+func intervalQuantity(iv Interval) (interface{}, bool) {
+	if e, ok := iv.(ExpressionInterval); ok {
+		return e.Quantity(), true
+	}
+	return nil, false
+}
+
+// hasFraction reports whether any of intervals is written in the fractional seconds.
+func hasFraction(intervals []Interval) bool {
 	for _, iv := range intervals {
-		v = iv.Value()
-		if v == 0 {
+		if u := iv.Unit(); u != Millisecond && u != Microsecond {
 			continue
 		}
-		buf = append(buf, " + INTERVAL "...)
-		buf = writeInt(buf, v)
+		if _, ok := intervalQuantity(iv); ok || iv.Value() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFraction writes v / div in decimal such as "1.500".
+func writeFraction(buf []byte, v int, div int, digits int) []byte {
+	if v < 0 {
+		buf, v = append(buf, '-'), -v
+	}
+	buf = writeInt(buf, v/div)
+	buf = append(buf, '.')
+	f := writeInt(nil, v%div)
+	for i := len(f); i < digits; i++ {
+		buf = append(buf, '0')
+	}
+	return append(buf, f...)
+}
+
+// AddInterval writes "l + INTERVAL n UNIT".
+// Millisecond is written in MICROSECOND because MySQL has no MILLISECOND.
+func (mySQL) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte {
+	buf = writeIntf(l, ctx, buf)
+	for _, iv := range intervals {
+		var unit string
+		scale := 1
 		switch iv.Unit() {
 		case Year:
-			buf = append(buf, " YEAR"...)
+			unit = " YEAR"
 		case Month:
-			buf = append(buf, " MONTH"...)
+			unit = " MONTH"
+		case Week:
+			unit = " WEEK"
 		case Day:
-			buf = append(buf, " DAY"...)
+			unit = " DAY"
 		case Hour:
-			buf = append(buf, " HOUR"...)
+			unit = " HOUR"
 		case Minute:
-			buf = append(buf, " MINUTE"...)
+			unit = " MINUTE"
 		case Second:
-			buf = append(buf, " SECOND"...)
+			unit = " SECOND"
+		case Millisecond:
+			unit, scale = " MICROSECOND", 1000
+		case Microsecond:
+			unit = " MICROSECOND"
 		default:
-			panic("unsupported interval unit type: " + string(writeInt(nil, int(iv.Unit()))))
+			setError(ctx, unsupportedUnit(iv.Unit()))
+			continue
+		}
+		v := iv.Value() * scale
+		q, ok := intervalQuantity(iv)
+		if !ok && v == 0 {
+			continue
 		}
+		buf = append(buf, " + INTERVAL "...)
+		if ok {
+			buf = writeIntf(q, ctx, buf)
+			if v != 1 {
+				buf = append(buf, " * "...)
+				buf = writeInt(buf, v)
+			}
+		} else {
+			buf = writeInt(buf, v)
+		}
+		buf = append(buf, unit...)
 	}
 	return buf
 }
 
+// AddInterval writes "l + INTERVAL 'n units'",
+// and the runtime quantity is multiplied such as "l + q * INTERVAL '1 day'".
 func (postgreSQL) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte {
-	var v int
 	buf = writeIntf(l, ctx, buf)
 	for _, iv := range intervals {
-		v = iv.Value()
-		if v == 0 {
-			continue
-		}
-		buf = append(buf, " + INTERVAL '"...)
-		buf = writeInt(buf, v)
+		var unit string
 		switch iv.Unit() {
 		case Year:
-			buf = append(buf, " year"...)
+			unit = " year"
 		case Month:
-			buf = append(buf, " month"...)
+			unit = " month"
+		case Week:
+			unit = " week"
 		case Day:
-			buf = append(buf, " day"...)
+			unit = " day"
 		case Hour:
-			buf = append(buf, " hour"...)
+			unit = " hour"
 		case Minute:
-			buf = append(buf, " minute"...)
+			unit = " minute"
 		case Second:
-			buf = append(buf, " second"...)
+			unit = " second"
+		case Millisecond:
+			unit = " millisecond"
+		case Microsecond:
+			unit = " microsecond"
 		default:
-			panic("unsupported interval unit type: " + string(writeInt(nil, int(iv.Unit()))))
+			setError(ctx, unsupportedUnit(iv.Unit()))
+			continue
+		}
+		v := iv.Value()
+		q, ok := intervalQuantity(iv)
+		if !ok && v == 0 {
+			continue
 		}
+		buf = append(buf, " + "...)
+		if ok {
+			buf = writeIntf(q, ctx, buf)
+			buf = append(buf, " * "...)
+		}
+		buf = append(buf, "INTERVAL '"...)
+		buf = writeInt(buf, v)
+		buf = append(buf, unit...)
 		if v != 1 {
 			buf = append(buf, 's')
 		}
@@ -109,34 +191,81 @@ func (postgreSQL) AddInterval(ctx *Context, buf []byte, l interface{}, intervals
 	return buf
 }
 
+// AddInterval writes DATETIME with the modifiers such as "DATETIME(l, 'n days')".
+// SQLite has no weeks and no units smaller than a second,
+// so they are converted into days and the fractional seconds.
+// DATETIME drops the fractional seconds, so STRFTIME with "%f" is written instead
+// if any interval is smaller than a second, and the result is rounded to milliseconds.
+// The runtime quantity is concatenated with the unit such as "DATETIME(l, q || ' days')".
 func (sqlite) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte {
-	var v int
-	buf = append(buf, "DATETIME("...)
+	if hasFraction(intervals) {
+		buf = append(buf, "STRFTIME('%Y-%m-%d %H:%M:%f', "...)
+	} else {
+		buf = append(buf, "DATETIME("...)
+	}
 	buf = writeIntf(l, ctx, buf)
 	for _, iv := range intervals {
-		v = iv.Value()
-		if v == 0 {
-			continue
-		}
-		buf = append(buf, ", '"...)
-		buf = writeInt(buf, v)
+		var unit string
+		scale, div := 1, 1
 		switch iv.Unit() {
 		case Year:
-			buf = append(buf, " year"...)
+			unit = " year"
 		case Month:
-			buf = append(buf, " month"...)
+			unit = " month"
+		case Week:
+			unit, scale = " day", 7
 		case Day:
-			buf = append(buf, " day"...)
+			unit = " day"
 		case Hour:
-			buf = append(buf, " hour"...)
+			unit = " hour"
 		case Minute:
-			buf = append(buf, " minute"...)
+			unit = " minute"
 		case Second:
-			buf = append(buf, " second"...)
+			unit = " second"
+		case Millisecond:
+			unit, div = " second", 1000
+		case Microsecond:
+			unit, div = " second", 1000000
 		default:
-			panic("unsupported interval unit type: " + string(writeInt(nil, int(iv.Unit()))))
+			setError(ctx, unsupportedUnit(iv.Unit()))
+			continue
 		}
-		if v != 1 {
+		v := iv.Value() * scale
+		q, ok := intervalQuantity(iv)
+		if !ok && v == 0 {
+			continue
+		}
+		buf = append(buf, ", "...)
+		if ok {
+			if v == 1 && div == 1 {
+				buf = writeIntf(q, ctx, buf)
+			} else {
+				buf = append(buf, '(')
+				buf = writeIntf(q, ctx, buf)
+				if v != 1 {
+					buf = append(buf, " * "...)
+					buf = writeInt(buf, v)
+				}
+				if div != 1 {
+					buf = append(buf, " / "...)
+					buf = writeInt(buf, div)
+					buf = append(buf, ".0"...)
+				}
+				buf = append(buf, ')')
+			}
+			buf = append(buf, " || '"...)
+			buf = append(buf, unit...)
+			buf = append(buf, "s'"...)
+			continue
+		}
+		buf = append(buf, '\'')
+		if div != 1 {
+			buf = writeFraction(buf, v, div, len(writeInt(nil, div))-1)
+		} else {
+			buf = writeInt(buf, v)
+		}
+		buf = append(buf, unit...)
+		if v != 1 || div != 1 {
 			buf = append(buf, 's')
 		}
 		buf = append(buf, '\'')
@@ -146,31 +275,5 @@ func (sqlite) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...
 }
 
 func (fakeDialect) AddInterval(ctx *Context, buf []byte, l interface{}, intervals ...Interval) []byte {
-	var v int
-	buf = writeIntf(l, ctx, buf)
-	for _, iv := range intervals {
-		v = iv.Value()
-		if v == 0 {
-			continue
-		}
-		buf = append(buf, " + INTERVAL "...)
-		buf = writeInt(buf, v)
-		switch iv.Unit() {
-		case Year:
-			buf = append(buf, " YEAR"...)
-		case Month:
-			buf = append(buf, " MONTH"...)
-		case Day:
-			buf = append(buf, " DAY"...)
-		case Hour:
-			buf = append(buf, " HOUR"...)
-		case Minute:
-			buf = append(buf, " MINUTE"...)
-		case Second:
-			buf = append(buf, " SECOND"...)
-		default:
-			panic("unsupported interval unit type: " + string(writeInt(nil, int(iv.Unit()))))
-		}
-	}
-	return buf
+	return mySQL{}.AddInterval(ctx, buf, l, intervals...)
 }
@@ -0,0 +1,43 @@
+package qutil
+
+func writeLike(ctx *Context, buf []byte, l, r interface{}, op string) []byte {
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, op...)
+	return writeIntf(r, ctx, buf)
+}
+
+func writeLowerLike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	buf = append(buf, "LOWER("...)
+	buf = writeIntf(l, ctx, buf)
+	if not {
+		buf = append(buf, ") NOT LIKE LOWER("...)
+	} else {
+		buf = append(buf, ") LIKE LOWER("...)
+	}
+	buf = writeIntf(r, ctx, buf)
+	return append(buf, ')')
+}
+
+// ILike writes LIKE because the comparison in MySQL follows the collation,
+// and the default collations are case-insensitive.
+func (mySQL) ILike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if not {
+		return writeLike(ctx, buf, l, r, " NOT LIKE ")
+	}
+	return writeLike(ctx, buf, l, r, " LIKE ")
+}
+
+func (postgreSQL) ILike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if not {
+		return writeLike(ctx, buf, l, r, " NOT ILIKE ")
+	}
+	return writeLike(ctx, buf, l, r, " ILIKE ")
+}
+
+func (sqlite) ILike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	return writeLowerLike(ctx, buf, l, r, not)
+}
+
+func (fakeDialect) ILike(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	return writeLowerLike(ctx, buf, l, r, not)
+}
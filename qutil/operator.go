@@ -0,0 +1,119 @@
+package qutil
+
+// operand is implemented by the values which may need parentheses as an operand of the operators.
+type operand interface {
+	WriteOperand(ctx *Context, buf []byte) []byte
+}
+
+// writeOperand writes x as an operand of the operators,
+// which is enclosed in parentheses if needed.
+func writeOperand(x interface{}, ctx *Context, buf []byte) []byte {
+	if v, ok := x.(operand); ok {
+		return v.WriteOperand(ctx, buf)
+	}
+	return writeIntf(x, ctx, buf)
+}
+
+func writeConcatOperator(ctx *Context, buf []byte, vs []interface{}) []byte {
+	for i, v := range vs {
+		if i > 0 {
+			buf = append(buf, " || "...)
+		}
+		buf = writeIntf(v, ctx, buf)
+	}
+	return buf
+}
+
+// writeXorEmulation writes "(l | r) - (l & r)" which is equivalent to l XOR r.
+func writeXorEmulation(ctx *Context, buf []byte, l, r interface{}) []byte {
+	buf = append(buf, '(')
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, " | "...)
+	buf = writeIntf(r, ctx, buf)
+	buf = append(buf, ") - ("...)
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, " & "...)
+	buf = writeIntf(r, ctx, buf)
+	return append(buf, ')')
+}
+
+// Concat writes CONCAT function because "||" means OR in MySQL by default.
+func (mySQL) Concat(ctx *Context, buf []byte, vs []interface{}) []byte {
+	buf = append(buf, "CONCAT("...)
+	for i, v := range vs {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = writeIntf(v, ctx, buf)
+	}
+	return append(buf, ')')
+}
+
+func (postgreSQL) Concat(ctx *Context, buf []byte, vs []interface{}) []byte {
+	return writeConcatOperator(ctx, buf, vs)
+}
+
+func (sqlite) Concat(ctx *Context, buf []byte, vs []interface{}) []byte {
+	return writeConcatOperator(ctx, buf, vs)
+}
+
+func (fakeDialect) Concat(ctx *Context, buf []byte, vs []interface{}) []byte {
+	return writeConcatOperator(ctx, buf, vs)
+}
+
+func (mySQL) BitXor(ctx *Context, buf []byte, l, r interface{}) []byte {
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, " ^ "...)
+	return writeIntf(r, ctx, buf)
+}
+
+func (postgreSQL) BitXor(ctx *Context, buf []byte, l, r interface{}) []byte {
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, " # "...)
+	return writeIntf(r, ctx, buf)
+}
+
+// BitXor writes the emulation because SQLite has no XOR operator.
+func (sqlite) BitXor(ctx *Context, buf []byte, l, r interface{}) []byte {
+	return writeXorEmulation(ctx, buf, l, r)
+}
+
+func (fakeDialect) BitXor(ctx *Context, buf []byte, l, r interface{}) []byte {
+	return writeXorEmulation(ctx, buf, l, r)
+}
+
+func writeNullSafeEq(ctx *Context, buf []byte, l, r interface{}, op string) []byte {
+	buf = writeIntf(l, ctx, buf)
+	buf = append(buf, op...)
+	return writeIntf(r, ctx, buf)
+}
+
+func (mySQL) NullSafeEq(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if !not {
+		return writeNullSafeEq(ctx, buf, l, r, " <=> ")
+	}
+	buf = append(buf, "NOT ("...)
+	buf = writeNullSafeEq(ctx, buf, l, r, " <=> ")
+	return append(buf, ')')
+}
+
+func (postgreSQL) NullSafeEq(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if not {
+		return writeNullSafeEq(ctx, buf, l, r, " IS DISTINCT FROM ")
+	}
+	return writeNullSafeEq(ctx, buf, l, r, " IS NOT DISTINCT FROM ")
+}
+
+func (sqlite) NullSafeEq(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if not {
+		return writeNullSafeEq(ctx, buf, l, r, " IS NOT ")
+	}
+	return writeNullSafeEq(ctx, buf, l, r, " IS ")
+}
+
+func (fakeDialect) NullSafeEq(ctx *Context, buf []byte, l, r interface{}, not bool) []byte {
+	if not {
+		return writeNullSafeEq(ctx, buf, l, r, " IS DISTINCT FROM ")
+	}
+	return writeNullSafeEq(ctx, buf, l, r, " IS NOT DISTINCT FROM ")
+}
@@ -0,0 +1,137 @@
+package qutil
+
+// writeRepeat writes "REPLACE(HEX(ZEROBLOB(n)), '00', v)" which repeats v n times,
+// because SQLite has no REPEAT.
+func writeRepeat(ctx *Context, buf []byte, v, n interface{}) []byte {
+	buf = append(buf, "REPLACE(HEX(ZEROBLOB("...)
+	buf = writeIntf(n, ctx, buf)
+	buf = append(buf, ")), '00', "...)
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, ')')
+}
+
+func (mySQL) ByteLength(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "LENGTH", v)
+}
+
+func (mySQL) Left(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "LEFT", v, n)
+}
+
+func (mySQL) Right(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "RIGHT", v, n)
+}
+
+func (mySQL) LPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return writeFunc(ctx, buf, "LPAD", v, n, pad)
+}
+
+func (mySQL) RPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return writeFunc(ctx, buf, "RPAD", v, n, pad)
+}
+
+func (mySQL) Repeat(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "REPEAT", v, n)
+}
+
+func (postgreSQL) ByteLength(ctx *Context, buf []byte, v interface{}) []byte {
+	return writeFunc(ctx, buf, "OCTET_LENGTH", v)
+}
+
+func (postgreSQL) Left(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "LEFT", v, n)
+}
+
+func (postgreSQL) Right(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "RIGHT", v, n)
+}
+
+func (postgreSQL) LPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return writeFunc(ctx, buf, "LPAD", v, n, pad)
+}
+
+func (postgreSQL) RPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return writeFunc(ctx, buf, "RPAD", v, n, pad)
+}
+
+func (postgreSQL) Repeat(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "REPEAT", v, n)
+}
+
+// ByteLength writes LENGTH of the value which is converted into BLOB,
+// because LENGTH counts the characters for TEXT.
+func (sqlite) ByteLength(ctx *Context, buf []byte, v interface{}) []byte {
+	buf = append(buf, "LENGTH(CAST("...)
+	buf = writeIntf(v, ctx, buf)
+	return append(buf, " AS BLOB))"...)
+}
+
+func (sqlite) Left(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeFunc(ctx, buf, "SUBSTR", v, raw("1"), n)
+}
+
+// Right writes "SUBSTR(v, -n, n)" which takes n characters from the end.
+func (sqlite) Right(ctx *Context, buf []byte, v, n interface{}) []byte {
+	buf = append(buf, "SUBSTR("...)
+	buf = writeIntf(v, ctx, buf)
+	buf = append(buf, ", -"...)
+	buf = writeOperand(n, ctx, buf)
+	buf = append(buf, ", "...)
+	buf = writeIntf(n, ctx, buf)
+	return append(buf, ')')
+}
+
+// LPad prepends pad which is repeated n times to v, and takes n characters.
+// As LPAD in MySQL and PostgreSQL, v is truncated if it is longer than n.
+func (sqlite) LPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	buf = append(buf, "SUBSTR(SUBSTR("...)
+	buf = writeRepeat(ctx, buf, pad, n)
+	buf = append(buf, ", 1, MAX("...)
+	buf = writeOperand(n, ctx, buf)
+	buf = append(buf, " - LENGTH("...)
+	buf = writeIntf(v, ctx, buf)
+	buf = append(buf, "), 0)) || "...)
+	buf = writeOperand(v, ctx, buf)
+	buf = append(buf, ", 1, "...)
+	buf = writeIntf(n, ctx, buf)
+	return append(buf, ')')
+}
+
+// RPad appends pad which is repeated n times to v, and takes n characters.
+func (sqlite) RPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	buf = append(buf, "SUBSTR("...)
+	buf = writeOperand(v, ctx, buf)
+	buf = append(buf, " || "...)
+	buf = writeRepeat(ctx, buf, pad, n)
+	buf = append(buf, ", 1, "...)
+	buf = writeIntf(n, ctx, buf)
+	return append(buf, ')')
+}
+
+func (sqlite) Repeat(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return writeRepeat(ctx, buf, v, n)
+}
+
+func (fakeDialect) ByteLength(ctx *Context, buf []byte, v interface{}) []byte {
+	return postgreSQL{}.ByteLength(ctx, buf, v)
+}
+
+func (fakeDialect) Left(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return postgreSQL{}.Left(ctx, buf, v, n)
+}
+
+func (fakeDialect) Right(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return postgreSQL{}.Right(ctx, buf, v, n)
+}
+
+func (fakeDialect) LPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return postgreSQL{}.LPad(ctx, buf, v, n, pad)
+}
+
+func (fakeDialect) RPad(ctx *Context, buf []byte, v, n, pad interface{}) []byte {
+	return postgreSQL{}.RPad(ctx, buf, v, n, pad)
+}
+
+func (fakeDialect) Repeat(ctx *Context, buf []byte, v, n interface{}) []byte {
+	return postgreSQL{}.Repeat(ctx, buf, v, n)
+}
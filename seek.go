@@ -0,0 +1,192 @@
+package q
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/oov/q/qutil"
+)
+
+// Seek adds the predicate which selects the rows after cursor in the order of b.Orders to the WHERE clause,
+// and sets LIMIT clause to n+1.
+// It implements the keyset pagination which doesn't get slow even if the page is deep unlike OFFSET.
+//
+// cursor is the values of the ORDER BY expressions of the last row in the previous page,
+// so it must have the same length as b.Orders. If cursor is nil, it selects the first page.
+// cursor often comes from the client through DecodeCursor, so the length mismatch doesn't panic
+// but is reported as an error by Validate and the executors.
+// The extra one row tells whether the next page exists, so it should be dropped before returning to the client.
+// The ORDER BY expressions should identify a row uniquely, such as a unique id at the end.
+//
+// The expressions which can be NULL must be passed to nullable,
+// or must be ordered with the explicit placement of NULLs such as Asc(e).NullsLast().
+// They are compared with considering where NULLs are sorted.
+// The ORDER BY expressions must be usable in the WHERE clause, so the alias name of the column can't be used.
+func (b *ZSelectBuilder) Seek(cursor []interface{}, n int, nullable ...Expression) *ZSelectBuilder {
+	if len(b.Orders) == 0 {
+		panic("q: need ORDER BY clause to seek.")
+	}
+	b.Limit(n + 1)
+	if cursor == nil {
+		return b
+	}
+	if len(cursor) != len(b.Orders) {
+		return b.Where(&seekExpr{Err: fmt.Errorf("q: invalid cursor: want %d values got %d", len(b.Orders), len(cursor))})
+	}
+	nulls := make(map[string]struct{}, len(nullable))
+	for _, e := range nullable {
+		nulls[expressionKey(e)] = struct{}{}
+	}
+	keys := make([]seekKey, len(b.Orders))
+	for i, o := range b.Orders {
+		_, isNullable := nulls[expressionKey(o.Expression)]
+		keys[i] = seekKey{Ordering: o, Nullable: isNullable || o.Nulls != NullsDefault, Value: cursor[i]}
+	}
+	return b.Where(&seekExpr{Keys: keys})
+}
+
+type seekKey struct {
+	Ordering
+	Nullable bool
+	Value    interface{}
+}
+
+// seekExpr represents the seek predicate which depends on where NULLs are sorted in the dialect.
+// If Err is not nil, it selects no rows and reports Err.
+type seekExpr struct {
+	Keys []seekKey
+	Err  error
+}
+
+func (e *seekExpr) String() string               { return expressionToString(e) }
+func (e *seekExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *seekExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if e.Err != nil {
+		setError(ctx, e.Err)
+		return append(buf, ctx.Dialect.BoolLiteral(false)...)
+	}
+	return e.expand(ctx.Dialect).WriteExpression(ctx, buf)
+}
+
+// expand returns the predicate such as "(a > ?)OR((a = ?)AND(b > ?))".
+func (e *seekExpr) expand(d qutil.Dialect) Expression {
+	if r := e.rowValue(); r != nil {
+		return r
+	}
+	var ors ZOrExpr
+	for i, k := range e.Keys {
+		after := k.after(d)
+		if after == nil {
+			continue
+		}
+		ands := make(ZAndExpr, 0, i+1)
+		for _, prev := range e.Keys[:i] {
+			if prev.Nullable {
+				ands = append(ands, NullSafeEq(prev.Expression, prev.Value))
+			} else {
+				ands = append(ands, Eq(prev.Expression, prev.Value))
+			}
+		}
+		ors = append(ors, append(ands, after))
+	}
+	if len(ors) == 0 {
+		return boolExpr(false)
+	}
+	return ors
+}
+
+// rowValue returns the row value comparison if all keys have the same direction and are not nullable.
+func (e *seekExpr) rowValue() Expression {
+	if len(e.Keys) < 2 {
+		return nil
+	}
+	l, r := make([]interface{}, len(e.Keys)), make([]interface{}, len(e.Keys))
+	for i, k := range e.Keys {
+		if k.Nullable || k.Ascending != e.Keys[0].Ascending {
+			return nil
+		}
+		l[i], r[i] = k.Expression, k.Value
+	}
+	if e.Keys[0].Ascending {
+		return Gt(Tuple(l...), Tuple(r...))
+	}
+	return Lt(Tuple(l...), Tuple(r...))
+}
+
+// after returns the predicate which selects the values after k.Value in the order,
+// or returns nil if there are no such values.
+func (k seekKey) after(d qutil.Dialect) Expression {
+	var cmp Expression
+	if k.Value != nil {
+		if k.Ascending {
+			cmp = Gt(k.Expression, k.Value)
+		} else {
+			cmp = Lt(k.Expression, k.Value)
+		}
+	}
+	if !k.Nullable {
+		return cmp
+	}
+	nullsLast := !k.nullsFirst(d)
+	switch {
+	case k.Value == nil && nullsLast:
+		return nil
+	case k.Value == nil:
+		return Neq(k.Expression, nil)
+	case nullsLast:
+		return Or(cmp, Eq(k.Expression, nil))
+	}
+	return cmp
+}
+
+// EncodeCursor encodes the cursor values into the opaque token which is safe to use in URLs.
+// The values are encoded as JSON, so they must be the types which can be encoded such as numbers and strings.
+func EncodeCursor(cursor []interface{}) (string, error) {
+	j, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(j), nil
+}
+
+// DecodeCursor decodes the token which is created by EncodeCursor.
+// Integers are decoded as int64 and the other numbers are decoded as float64.
+// Note that the types which are encoded as JSON strings such as time.Time are decoded as string.
+// The token comes from the client, so it returns an error if the values are not scalar such as arrays and objects.
+func DecodeCursor(token string) ([]interface{}, error) {
+	j, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("q: invalid cursor: " + err.Error())
+	}
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+	var r []interface{}
+	if err = d.Decode(&r); err != nil {
+		return nil, errors.New("q: invalid cursor: " + err.Error())
+	}
+	if r == nil {
+		return nil, errors.New("q: invalid cursor: not an array")
+	}
+	for i, v := range r {
+		var n json.Number
+		switch v := v.(type) {
+		case nil, bool, string:
+			continue
+		case json.Number:
+			n = v
+		default:
+			return nil, fmt.Errorf("q: invalid cursor: values[%d] is not a scalar", i)
+		}
+		if x, err := n.Int64(); err == nil {
+			r[i] = x
+		} else if x, err := n.Float64(); err == nil {
+			r[i] = x
+		} else {
+			return nil, errors.New("q: invalid cursor: " + err.Error())
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,165 @@
+package q
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+var seekTests = []struct {
+	Name string
+	B    *ZSelectBuilder
+	D    qutil.Dialect
+	SQL  string
+	Args string
+}{
+	{
+		Name: "first page",
+		B:    Select().From(T("post")).OrderBy(C("id"), true).Seek(nil, 20),
+		D:    PostgreSQL,
+		SQL:  `SELECT * FROM "post" ORDER BY "id" ASC LIMIT $1`,
+		Args: "[21]",
+	},
+	{
+		Name: "single key",
+		B:    Select().From(T("post")).OrderBy(C("id"), false).Seek([]interface{}{10}, 20),
+		D:    PostgreSQL,
+		SQL:  `SELECT * FROM "post" WHERE "id" < $1 ORDER BY "id" DESC LIMIT $2`,
+		Args: "[10 21]",
+	},
+	{
+		Name: "row value",
+		B:    Select().From(T("post")).Where(Eq(C("draft"), false)).OrderBy(C("created_at"), false).OrderBy(C("id"), false).Seek([]interface{}{"2020-01-01", 10}, 20),
+		D:    MySQL,
+		SQL:  "SELECT * FROM `post` WHERE (`draft` = ?)AND((`created_at`, `id`) < (?, ?)) ORDER BY `created_at` DESC, `id` DESC LIMIT ?",
+		Args: "[false 2020-01-01 10 21]",
+	},
+	{
+		Name: "row value emulation",
+		B:    Select().From(T("post")).OrderBy(C("created_at"), true).OrderBy(C("id"), true).Seek([]interface{}{"2020-01-01", 10}, 20),
+		D:    SQLite,
+		SQL:  `SELECT * FROM "post" WHERE ("created_at" > ?)OR(("created_at" = ?)AND("id" > ?)) ORDER BY "created_at" ASC, "id" ASC LIMIT ?`,
+		Args: "[2020-01-01 2020-01-01 10 21]",
+	},
+	{
+		Name: "mixed order",
+		B:    Select().From(T("post")).OrderBy(C("created_at"), false).OrderBy(C("id"), true).Seek([]interface{}{"2020-01-01", 10}, 20),
+		D:    PostgreSQL,
+		SQL:  `SELECT * FROM "post" WHERE ("created_at" < $1)OR(("created_at" = $2)AND("id" > $3)) ORDER BY "created_at" DESC, "id" ASC LIMIT $4`,
+		Args: "[2020-01-01 2020-01-01 10 21]",
+	},
+	{
+		Name: "nullable nulls last",
+		B:    Select().From(T("post")).OrderBy(C("score"), true).OrderBy(C("id"), true).Seek([]interface{}{5, 10}, 20, C("score")),
+		D:    PostgreSQL,
+		SQL:  `SELECT * FROM "post" WHERE (("score" > $1)OR("score" IS NULL))OR(("score" IS NOT DISTINCT FROM $2)AND("id" > $3)) ORDER BY "score" ASC, "id" ASC LIMIT $4`,
+		Args: "[5 5 10 21]",
+	},
+	{
+		Name: "nullable nulls last with null cursor",
+		B:    Select().From(T("post")).OrderBy(C("score"), true).OrderBy(C("id"), true).Seek([]interface{}{nil, 10}, 20, C("score")),
+		D:    PostgreSQL,
+		SQL:  `SELECT * FROM "post" WHERE ("score" IS NULL)AND("id" > $1) ORDER BY "score" ASC, "id" ASC LIMIT $2`,
+		Args: "[10 21]",
+	},
+	{
+		Name: "nullable nulls first",
+		B:    Select().From(T("post")).OrderBy(C("score"), true).OrderBy(C("id"), true).Seek([]interface{}{5, 10}, 20, C("score")),
+		D:    MySQL,
+		SQL:  "SELECT * FROM `post` WHERE (`score` > ?)OR((`score` <=> ?)AND(`id` > ?)) ORDER BY `score` ASC, `id` ASC LIMIT ?",
+		Args: "[5 5 10 21]",
+	},
+	{
+		Name: "nullable nulls first with null cursor",
+		B:    Select().From(T("post")).OrderBy(C("score"), true).OrderBy(C("id"), true).Seek([]interface{}{nil, 10}, 20, C("score")),
+		D:    SQLite,
+		SQL:  `SELECT * FROM "post" WHERE ("score" IS NOT NULL)OR(("score" IS NULL)AND("id" > ?)) ORDER BY "score" ASC, "id" ASC LIMIT ?`,
+		Args: "[10 21]",
+	},
+	{
+		Name: "nullable desc",
+		B:    Select().From(T("post")).OrderBy(C("score"), false).Seek([]interface{}{5}, 20, C("score")),
+		D:    SQLite,
+		SQL:  `SELECT * FROM "post" WHERE ("score" < ?)OR("score" IS NULL) ORDER BY "score" DESC LIMIT ?`,
+		Args: "[5 21]",
+	},
+	{
+		Name: "no more rows",
+		B:    Select().From(T("post")).OrderBy(C("score"), false).Seek([]interface{}{nil}, 20, C("score")),
+		D:    SQLite,
+		SQL:  `SELECT * FROM "post" WHERE 1=0 ORDER BY "score" DESC LIMIT ?`,
+		Args: "[21]",
+	},
+}
+
+func TestSeek(t *testing.T) {
+	for i, test := range seekTests {
+		sql, args := test.B.SetDialect(test.D).ToSQL()
+		if sql != test.SQL {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.SQL, sql)
+		}
+		if got := fmt.Sprint(args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+	}
+}
+
+func TestSeekPanic(t *testing.T) {
+	for i, f := range []func(){
+		func() { Select().From(T("post")).Seek([]interface{}{1}, 10) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("tests[%d] want panic", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestSeekInvalidCursor(t *testing.T) {
+	for i, token := range []string{"W10", "WzEsMl0"} {
+		cursor, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("tests[%d] %q Error: %v", i, token, err)
+		}
+		b := Select().From(T("post")).OrderBy(C("id"), true).Seek(cursor, 10)
+		if err := Validate(b); err == nil {
+			t.Errorf("tests[%d] %q want error got nil", i, token)
+		}
+		if err := Validate(clone(b).(*ZSelectBuilder)); err == nil {
+			t.Errorf("tests[%d] %q clone want error got nil", i, token)
+		}
+		if want, got := `SELECT * FROM "post" WHERE 1=0 ORDER BY "id" ASC LIMIT ? [11]`, b.SetDialect(SQLite).String(); want != got {
+			t.Errorf("tests[%d] %q want %s got %s", i, token, want, got)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor := []interface{}{int64(10), "a/b+c", 1.5, nil, true}
+	token, err := EncodeCursor(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cursor, r) {
+		t.Errorf("want %#v got %#v", cursor, r)
+	}
+
+	for i, token := range []string{"!!!", "e30", "", "WzFd!", "W3t9XQ", "W1sxXV0"} {
+		if _, err := DecodeCursor(token); err == nil {
+			t.Errorf("tests[%d] %q want error got nil", i, token)
+		}
+	}
+	if _, err := EncodeCursor([]interface{}{func() {}}); err == nil {
+		t.Errorf("want error got nil")
+	}
+}
@@ -1,22 +1,27 @@
 // Package q implements a SQL builder.
 package q
 
-import "github.com/oov/q/qutil"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/oov/q/qutil"
+)
 
 // ZSelectBuilder implemenets a SELECT builder.
 // This also implements Expression interface, so it can use in many place.
 type ZSelectBuilder struct {
-	Dialect   qutil.Dialect
-	Beginning string
-	Columns   []Column
-	Tables    []Table
-	Wheres    ZAndExpr
-	Groups    []Expression
-	Havings   ZAndExpr
-	Orders    []struct {
-		Expression
-		Ascending bool
-	}
+	Dialect     qutil.Dialect
+	Beginning   string
+	IsDistinct  bool
+	DistinctOns []Expression
+	Columns     []Column
+	Tables      []Table
+	Wheres      ZAndExpr
+	Groups      []Expression
+	Havings     ZAndExpr
+	Orders      []Ordering
 	LimitCount  Expression
 	StartOffset Expression
 }
@@ -43,6 +48,59 @@ func (b *ZSelectBuilder) SetDialect(d qutil.Dialect) *ZSelectBuilder {
 	return b
 }
 
+// Clone returns a deep copy of the builder.
+// Modifying the copy never affects the original, so a base query can be shared.
+func (b *ZSelectBuilder) Clone() *ZSelectBuilder {
+	return clone(b).(*ZSelectBuilder)
+}
+
+// CountQuery returns a new builder which counts the rows which are selected by the builder.
+//
+// The copy of the builder without ORDER BY, LIMIT and OFFSET clauses is used,
+// and its columns are replaced by "COUNT(*)".
+// If the builder has GROUP BY, HAVING or DISTINCT, the number of rows can't be counted by replacing the columns,
+// so the copy is wrapped as a subquery such as "SELECT COUNT(*) FROM (SELECT ...) AS "t"".
+// The columns of the subquery are replaced by "1" unless they are needed by DISTINCT or HAVING clause,
+// and the columns which have the same name are aliased because the subquery can't have the duplicated names.
+// The builder itself is not modified, so it can be used to select the rows of the page.
+func (b *ZSelectBuilder) CountQuery() *ZSelectBuilder {
+	c := b.Clone()
+	c.Orders, c.LimitCount, c.StartOffset = nil, nil, nil
+	distinct := c.isDistinct()
+	if len(c.Groups) == 0 && len(c.Havings) == 0 && !distinct {
+		c.Columns = []Column{CountAll().C()}
+		return c
+	}
+	if !distinct && (len(c.Havings) == 0 || len(c.Columns) == 0) {
+		c.Columns = []Column{Unsafe("1").C()}
+	} else {
+		c.Columns = uniqueColumns(c.Columns)
+	}
+	return Select().From(c.T("t")).Column(CountAll().C()).SetDialect(b.Dialect)
+}
+
+// uniqueColumns returns the copy of cols whose duplicated output names are replaced by the unique alias names.
+// The names are compared case-insensitively as MySQL does.
+func uniqueColumns(cols []Column) []Column {
+	r := make([]Column, len(cols))
+	names := map[string]struct{}{}
+	for i, c := range cols {
+		name := strings.ToLower(outputName(c))
+		if _, ok := names[name]; ok && name != "" {
+			name = "q_c" + strconv.Itoa(i)
+			c = c.C(name)
+		}
+		names[name] = struct{}{}
+		r[i] = c
+	}
+	return r
+}
+
+// isDistinct reports whether the builder selects distinct rows.
+func (b *ZSelectBuilder) isDistinct() bool {
+	return b.IsDistinct || len(b.DistinctOns) > 0 || strings.Contains(strings.ToUpper(b.Beginning), "DISTINCT")
+}
+
 // Column appends a column to the column list.
 func (b *ZSelectBuilder) Column(columns ...Column) *ZSelectBuilder {
 	b.Columns = append(b.Columns, columns...)
@@ -75,6 +133,8 @@ func (b *ZSelectBuilder) Offset(start interface{}) *ZSelectBuilder {
 }
 
 // GroupBy adds condition to the GROUP BY clause.
+// Rollup, Cube and GroupingSets can be used as the elements.
+// If the dialect doesn't support them, the error is reported by Validate and ZExecutor.
 func (b *ZSelectBuilder) GroupBy(e ...Expression) *ZSelectBuilder {
 	b.Groups = append(b.Groups, e...)
 	return b
@@ -89,15 +149,59 @@ func (b *ZSelectBuilder) Having(conds ...Expression) *ZSelectBuilder {
 
 // OrderBy adds condition to the ORDER BY clause.
 func (b *ZSelectBuilder) OrderBy(e Expression, asc bool) *ZSelectBuilder {
-	b.Orders = append(b.Orders, struct {
-		Expression
-		Ascending bool
-	}{e, asc})
+	b.Orders = append(b.Orders, Ordering{Expression: e, Ascending: asc})
+	return b
+}
+
+// Order adds orderings to the ORDER BY clause,
+// such as Order(Desc(C("score")).NullsLast(), Asc(C("name")).Collate("C")).
+func (b *ZSelectBuilder) Order(orders ...Ordering) *ZSelectBuilder {
+	b.Orders = append(b.Orders, orders...)
+	return b
+}
+
+// Distinct makes the builder select only distinct rows such as "SELECT DISTINCT".
+func (b *ZSelectBuilder) Distinct() *ZSelectBuilder {
+	b.IsDistinct = true
+	return b
+}
+
+// DistinctOn makes the builder select only the first row of each set of rows
+// where exprs are equal, such as "SELECT DISTINCT ON (exprs)" in PostgreSQL.
+// The first row is decided by ORDER BY clause.
+//
+// In the other dialects, it is emulated by the subquery which uses ROW_NUMBER window function, such as
+// "SELECT "a", "b" FROM (SELECT "a", "b", ROW_NUMBER() OVER (PARTITION BY exprs ORDER BY ...) AS "q_rn" FROM ...) AS "q_t" WHERE "q_rn" = 1".
+// So the columns must be given explicitly, otherwise the error is reported by Validate.
+// The columns which have no name such as COUNT(*) and the columns which have the same name
+// such as "u"."id" and "p"."id" are given the alias names such as "q_c0" in the subquery.
+func (b *ZSelectBuilder) DistinctOn(exprs ...Expression) *ZSelectBuilder {
+	b.DistinctOns = append(b.DistinctOns, exprs...)
 	return b
 }
 
 func (b *ZSelectBuilder) write(ctx *qutil.Context, buf []byte) []byte {
+	if len(b.DistinctOns) > 0 && !ctx.Dialect.CanUseDistinctOn() {
+		if len(b.Columns) > 0 {
+			return b.emulateDistinctOn().write(ctx, buf)
+		}
+		setError(ctx, fmt.Errorf("q: need columns to emulate DISTINCT ON in %v", ctx.Dialect))
+	}
+
 	buf = append(buf, b.Beginning...)
+	if b.IsDistinct {
+		buf = append(buf, " DISTINCT"...)
+	}
+	if len(b.DistinctOns) > 0 {
+		buf = append(buf, " DISTINCT ON ("...)
+		for i, e := range b.DistinctOns {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = e.WriteExpression(ctx, buf)
+		}
+		buf = append(buf, ')')
+	}
 
 	if len(b.Columns) == 0 {
 		buf = append(buf, " *"...)
@@ -113,7 +217,8 @@ func (b *ZSelectBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 	if len(b.Tables) == 0 {
 		// FROM DUAL?
 	} else {
-		buf = append(buf, " FROM "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "FROM "...)
 		buf = b.Tables[0].WriteDefinition(ctx, buf)
 		for _, t := range b.Tables[1:] {
 			buf = append(buf, ", "...)
@@ -122,46 +227,38 @@ func (b *ZSelectBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 	}
 
 	if len(b.Wheres) > 0 {
-		buf = append(buf, " WHERE "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "WHERE "...)
 		buf = b.Wheres.WriteExpression(ctx, buf)
 	}
 
 	if len(b.Groups) > 0 {
-		buf = append(buf, " GROUP BY "...)
-		buf = b.Groups[0].WriteExpression(ctx, buf)
-		for _, g := range b.Groups[1:] {
-			buf = append(buf, ", "...)
-			buf = g.WriteExpression(ctx, buf)
-		}
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "GROUP BY "...)
+		buf = writeGroups(ctx, buf, b.Groups)
 	}
 
 	if len(b.Havings) > 0 {
-		buf = append(buf, " HAVING "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "HAVING "...)
 		buf = b.Havings.WriteExpression(ctx, buf)
 	}
 
 	if len(b.Orders) > 0 {
-		buf = append(buf, " ORDER BY "...)
-		for i, o := range b.Orders {
-			if i > 0 {
-				buf = append(buf, ", "...)
-			}
-			buf = o.Expression.WriteExpression(ctx, buf)
-			if o.Ascending {
-				buf = append(buf, " ASC"...)
-			} else {
-				buf = append(buf, " DESC"...)
-			}
-		}
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "ORDER BY "...)
+		buf = writeOrders(ctx, buf, b.Orders)
 	}
 
 	if b.LimitCount != nil {
-		buf = append(buf, " LIMIT "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "LIMIT "...)
 		buf = b.LimitCount.WriteExpression(ctx, buf)
 	}
 
 	if b.StartOffset != nil {
-		buf = append(buf, " OFFSET "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "OFFSET "...)
 		buf = b.StartOffset.WriteExpression(ctx, buf)
 	}
 
@@ -195,8 +292,5 @@ func (b *ZSelectBuilder) C(aliasName ...string) Column {
 
 // WriteExpression implements Expression interface.
 func (b *ZSelectBuilder) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
-	buf = append(buf, '(')
-	buf = b.write(ctx, buf)
-	buf = append(buf, ')')
-	return buf
+	return writeSubquery(b, ctx, buf)
 }
@@ -300,6 +300,83 @@ func TestSelect(t *testing.T) {
 	}
 }
 
+func TestSelectClone(t *testing.T) {
+	for i, test := range selectTests {
+		if r := fmt.Sprint(test.B.Clone()); r != test.V {
+			t.Errorf("tests[%d] %s: want %s got %s", i, test.Name, test.V, r)
+		}
+	}
+
+	user := T("user", "u")
+	name := user.C("name", "n")
+	base := Select().From(user).Column(name).Where(Gt(user.C("age"), 18))
+	want := base.String()
+
+	count := base.Clone()
+	count.Columns = []Column{CountAll().C("c")}
+	count.Where(Eq(user.C("deleted"), false))
+
+	page := base.Clone().OrderBy(user.C("id"), true).Limit(10)
+	page.Columns[0] = page.Columns[0].C("renamed")
+	page.Tables[0] = page.Tables[0].InnerJoin(T("post", "p"), Eq(T("post", "p").C("user_id"), user.C("id")))
+
+	if got := base.String(); got != want {
+		t.Errorf("the base builder was modified: want %s got %s", want, got)
+	}
+	if want, got := `SELECT COUNT(*) AS "c" FROM "user" AS "u" WHERE ("u"."age" > ?)AND("u"."deleted" = ?) [18 false]`, count.String(); got != want {
+		t.Errorf("want %s got %s", want, got)
+	}
+	if want, got := `SELECT "u"."name" AS "renamed" FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id" WHERE "u"."age" > ? ORDER BY "u"."id" ASC LIMIT ? [18 10]`, page.String(); got != want {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestSelectCountQuery(t *testing.T) {
+	user, post := T("user", "u"), T("post", "p")
+	for i, test := range []struct {
+		B *ZSelectBuilder
+		V string
+	}{
+		{
+			B: Select().Column(user.C("id"), user.C("name")).From(user).Where(Gt(user.C("age"), 18)).OrderBy(user.C("id"), true).Limit(10).Offset(20),
+			V: `SELECT COUNT(*) FROM "user" AS "u" WHERE "u"."age" > $1 [18]`,
+		},
+		{
+			B: Select().Column(user.C("age"), CountAll().C("c")).From(user).Where(Gt(user.C("age"), 18)).GroupBy(user.C("age")).Limit(10),
+			V: `SELECT COUNT(*) FROM (SELECT 1 FROM "user" AS "u" WHERE "u"."age" > $1 GROUP BY "u"."age") AS "t" [18]`,
+		},
+		{
+			B: Select().Column(user.C("age"), CountAll().C("c")).From(user).GroupBy(user.C("age")).Having(Gt(C("c"), 1)),
+			V: `SELECT COUNT(*) FROM (SELECT "u"."age", COUNT(*) AS "c" FROM "user" AS "u" GROUP BY "u"."age" HAVING "c" > $1) AS "t" [1]`,
+		},
+		{
+			B: Select().Column(user.C("id"), post.C("id"), post.C("title", "ID")).From(user.InnerJoin(post, Eq(post.C("user_id"), user.C("id")))).GroupBy(user.C("id"), post.C("id"), post.C("title")).Having(Gt(CountAll(), 0)),
+			V: `SELECT COUNT(*) FROM (SELECT "u"."id", "p"."id" AS "q_c1", "p"."title" AS "q_c2" FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id" GROUP BY "u"."id", "p"."id", "p"."title" HAVING COUNT(*) > $1) AS "t" [0]`,
+		},
+		{
+			B: Select("SELECT DISTINCT").Column(user.C("id"), post.C("id")).From(user.InnerJoin(post, Eq(post.C("user_id"), user.C("id")))),
+			V: `SELECT COUNT(*) FROM (SELECT DISTINCT "u"."id", "p"."id" AS "q_c1" FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id") AS "t" []`,
+		},
+		{
+			B: Select().From(user).GroupBy(user.C("age")).Having(Gt(CountAll(), 1)),
+			V: `SELECT COUNT(*) FROM (SELECT 1 FROM "user" AS "u" GROUP BY "u"."age" HAVING COUNT(*) > $1) AS "t" [1]`,
+		},
+		{
+			B: Select("SELECT DISTINCT").Column(user.C("name")).From(user).Where(Eq(user.C("id"), []int{1, 2})).OrderBy(user.C("name"), true),
+			V: `SELECT COUNT(*) FROM (SELECT DISTINCT "u"."name" FROM "user" AS "u" WHERE "u"."id" IN ($1,$2)) AS "t" [1 2]`,
+		},
+	} {
+		want := test.B.SetDialect(PostgreSQL).String()
+		sql, args := test.B.CountQuery().ToSQL()
+		if got := sql + " " + fmt.Sprint(args); got != test.V {
+			t.Errorf("tests[%d] want %s got %s", i, test.V, got)
+		}
+		if got := test.B.String(); got != want {
+			t.Errorf("tests[%d] the original builder was modified: want %s got %s", i, want, got)
+		}
+	}
+}
+
 func exec(t *testing.T, name string, db *sql.DB, d qutil.Dialect, sqls []string) {
 	for i, sql := range sqls {
 		if _, err := db.Exec(sql); err != nil {
@@ -0,0 +1,177 @@
+package q
+
+import (
+	"fmt"
+
+	"github.com/oov/q/qutil"
+)
+
+// boolExpr represents the boolean literal which is written in the dialect specific form.
+type boolExpr bool
+
+func (e boolExpr) String() string               { return expressionToString(e) }
+func (e boolExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e boolExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return append(buf, ctx.Dialect.BoolLiteral(bool(e))...)
+}
+
+// logicalExpr represents flattened AND/OR Expression which is generated by Simplify.
+// Unlike ZAndExpr and ZOrExpr, it encloses the operands in parentheses
+// only if the operator precedence requires.
+type logicalExpr struct {
+	And   bool
+	Exprs []Expression
+}
+
+func (e *logicalExpr) String() string               { return expressionToString(e) }
+func (e *logicalExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *logicalExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	op := "OR "
+	if e.And {
+		op = "AND "
+	}
+	ctx.Indent++
+	for i, x := range e.Exprs {
+		if i > 0 {
+			buf = writeSeparator(ctx, buf)
+			buf = append(buf, op...)
+		}
+		if needsParentheses(x, e.And) {
+			buf = append(buf, '(')
+			buf = x.WriteExpression(ctx, buf)
+			buf = append(buf, ')')
+			continue
+		}
+		buf = x.WriteExpression(ctx, buf)
+	}
+	ctx.Indent--
+	return buf
+}
+
+// needsParentheses reports whether e must be enclosed in parentheses as an operand of AND or OR.
+func needsParentheses(e Expression, and bool) bool {
+	switch v := e.(type) {
+	case *logicalExpr:
+		return and && !v.And
+	case boolExpr, *eqExpr, *neqExpr, *gtExpr, *gteExpr, *ltExpr, *lteExpr,
+		*inExpr, *notInExpr, *simpleInExpr, *simpleNotInExpr, *notExpr, *existsExpr, *likeExpr, *nullSafeEqExpr:
+		return false
+	}
+	return true
+}
+
+// Simplify returns a copy of n whose AND/OR expressions are normalized.
+//
+// Nested And and Or are flattened and duplicated conditions are removed.
+// Expressions whose results are obvious, such as empty And, empty Or and IN with an empty list,
+// are replaced by the boolean literal of the dialect such as TRUE/FALSE or 1=1/1=0,
+// and they are folded into the enclosing And or Or.
+// The simplified AND/OR is written with parentheses only where the operator precedence requires.
+//
+// n is one of Expression or the builders.
+// If n is a builder, the conditions of WHERE, HAVING and joins are also simplified,
+// and WHERE and HAVING which are always true are removed.
+func Simplify(n interface{}) interface{} {
+	return Transform(n, simplifyNode)
+}
+
+func simplifyNode(n interface{}) interface{} {
+	switch v := n.(type) {
+	case ZAndExpr:
+		return simplifyLogical(true, v)
+	case ZOrExpr:
+		return simplifyLogical(false, v)
+	case *logicalExpr:
+		return simplifyLogical(v.And, v.Exprs)
+	case *inExpr:
+		if len(v.Right) == 0 {
+			return boolExpr(false)
+		}
+	case *notInExpr:
+		if len(v.Right) == 0 {
+			return boolExpr(true)
+		}
+	case *tupleInExpr:
+		if len(v.Right) == 0 {
+			return boolExpr(v.Not)
+		}
+	case *table:
+		simplifyJoins(v.Joins)
+	case *selectBuilderAsTable:
+		simplifyJoins(v.Joins)
+	case *ZSelectBuilder:
+		v.Wheres = simplifyConds(v.Wheres)
+		v.Havings = simplifyConds(v.Havings)
+	case *ZUpdateBuilder:
+		v.Wheres = simplifyConds(v.Wheres)
+	case *ZDeleteBuilder:
+		v.Wheres = simplifyConds(v.Wheres)
+	}
+	return n
+}
+
+// simplifyConds simplifies the conditions of WHERE or HAVING.
+// It returns nil if the conditions are always true.
+func simplifyConds(conds ZAndExpr) ZAndExpr {
+	if len(conds) == 0 {
+		return conds
+	}
+	e := simplifyLogical(true, conds)
+	if b, ok := e.(boolExpr); ok && bool(b) {
+		return nil
+	}
+	return ZAndExpr{e}
+}
+
+// simplifyJoins simplifies the join conditions in place.
+// joins must be a copy which is created by Transform.
+func simplifyJoins(joins []join) {
+	for i, j := range joins {
+		if len(j.Conds) > 0 {
+			joins[i].Conds = ZAndExpr{simplifyLogical(true, j.Conds)}
+		}
+	}
+}
+
+// simplifyLogical flattens exprs, removes duplicates and folds boolean literals.
+// exprs must be simplified already.
+func simplifyLogical(and bool, exprs []Expression) Expression {
+	var r []Expression
+	seen := map[string]struct{}{}
+	for _, e := range exprs {
+		operands := []Expression{e}
+		if l, ok := e.(*logicalExpr); ok && l.And == and {
+			operands = l.Exprs
+		}
+		for _, x := range operands {
+			if b, ok := x.(boolExpr); ok {
+				if bool(b) == and {
+					// TRUE in AND, FALSE in OR doesn't affect the result.
+					continue
+				}
+				// FALSE in AND, TRUE in OR decides the result.
+				return b
+			}
+			k := expressionKey(x)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			r = append(r, x)
+		}
+	}
+	switch len(r) {
+	case 0:
+		return boolExpr(and)
+	case 1:
+		return r[0]
+	}
+	return &logicalExpr{And: and, Exprs: r}
+}
+
+// expressionKey returns the string which identifies e including its arguments.
+func expressionKey(e Expression) string {
+	buf, ctx := qutil.NewContext(e, 32, 1, nil)
+	buf = e.WriteExpression(ctx, buf)
+	return fmt.Sprintf("%s %#v %v", buf, ctx.Args, ctx.ArgsMap)
+}
@@ -0,0 +1,147 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+var simplifyTests = []struct {
+	Name string
+	E    Expression
+	V    string
+}{
+	{
+		Name: "flatten",
+		E:    And(Eq(C("a"), 1), And(Eq(C("b"), 2), And(Eq(C("c"), 3)))),
+		V:    `"a" = ? AND "b" = ? AND "c" = ? [1 2 3]`,
+	},
+	{
+		Name: "precedence",
+		E:    And(Or(Eq(C("a"), 1), Eq(C("b"), 2)), Eq(C("c"), 3)),
+		V:    `("a" = ? OR "b" = ?) AND "c" = ? [1 2 3]`,
+	},
+	{
+		Name: "and in or",
+		E:    Or(And(Eq(C("a"), 1), Eq(C("b"), 2)), Or(Eq(C("c"), 3))),
+		V:    `"a" = ? AND "b" = ? OR "c" = ? [1 2 3]`,
+	},
+	{
+		Name: "unknown operand",
+		E:    And(Eq(C("a"), 1), Unsafe("b OR c")),
+		V:    `"a" = ? AND (b OR c) [1]`,
+	},
+	{
+		Name: "duplicates",
+		E:    And(Eq(C("a"), 1), Eq(C("a"), 1), Eq(C("a"), "1"), Eq(C("a"), 2), And(Eq(C("a"), 1))),
+		V:    `"a" = ? AND "a" = ? AND "a" = ? [1 1 2]`,
+	},
+	{
+		Name: "duplicates to single",
+		E:    Or(Eq(C("a"), 1), Eq(C("a"), 1)),
+		V:    `"a" = ? [1]`,
+	},
+	{
+		Name: "empty and",
+		E:    And(),
+		V:    `TRUE []`,
+	},
+	{
+		Name: "empty or",
+		E:    Or(),
+		V:    `FALSE []`,
+	},
+	{
+		Name: "empty in",
+		E:    And(Eq(C("a"), 1), In(C("b"), []int{})),
+		V:    `FALSE []`,
+	},
+	{
+		Name: "empty not in",
+		E:    And(Eq(C("a"), 1), Neq(C("b"), []int{})),
+		V:    `"a" = ? [1]`,
+	},
+	{
+		Name: "empty and in or",
+		E:    Or(Eq(C("a"), 1), And()),
+		V:    `TRUE []`,
+	},
+	{
+		Name: "empty or in or",
+		E:    Or(Eq(C("a"), 1), Or(), In(C("b"), []int{})),
+		V:    `"a" = ? [1]`,
+	},
+	{
+		Name: "nested in expression",
+		E:    Eq(Case().When(And(Eq(C("a"), 1), And(Eq(C("b"), 2))), 1).Else(0), 1),
+		V:    `CASE WHEN "a" = ? AND "b" = ? THEN ? ELSE ? END = ? [1 2 1 0 1]`,
+	},
+}
+
+func TestSimplify(t *testing.T) {
+	for i, test := range simplifyTests {
+		before := fmt.Sprint(test.E)
+		r := Simplify(test.E).(Expression)
+		if got := fmt.Sprint(r); got != test.V {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.V, got)
+		}
+		if got := fmt.Sprint(test.E); got != before {
+			t.Errorf("tests[%d] %s the original expression was modified: %s", i, test.Name, got)
+		}
+	}
+}
+
+func TestSimplifyBuilder(t *testing.T) {
+	user, post := T("user", "u"), T("post", "p")
+	sel := Select().From(
+		user.InnerJoin(post, Eq(post.C("user_id"), user.C("id")), Eq(post.C("user_id"), user.C("id"))),
+	).Where(
+		Eq(user.C("deleted"), false),
+		Or(Eq(user.C("id"), 1), In(user.C("id"), []int{})),
+		Eq(user.C("deleted"), false),
+	).GroupBy(user.C("id")).Having(And())
+	r := Simplify(sel).(*ZSelectBuilder)
+	want := `SELECT * FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id" WHERE "u"."deleted" = ? AND "u"."id" = ? GROUP BY "u"."id" [false 1]`
+	if got := r.String(); got != want {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	for i, test := range []struct {
+		B Builder
+		D qutil.Dialect
+		V string
+	}{
+		{B: Update(T("user")).Set(C("a"), 1).Where(Or()), D: SQLite, V: `UPDATE "user" SET "a" = ? WHERE 1=0`},
+		{B: Delete(T("user")).Where(Neq(C("id"), []int{})), D: PostgreSQL, V: `DELETE FROM "user"`},
+		{B: Select().From(T("user")).Where(And(), Or()), D: MySQL, V: "SELECT * FROM `user` WHERE FALSE"},
+	} {
+		r := Simplify(test.B).(Builder)
+		var sql string
+		switch b := r.(type) {
+		case *ZUpdateBuilder:
+			sql, _ = b.SetDialect(test.D).ToSQL()
+		case *ZDeleteBuilder:
+			sql, _ = b.SetDialect(test.D).ToSQL()
+		case *ZSelectBuilder:
+			sql, _ = b.SetDialect(test.D).ToSQL()
+		}
+		if sql != test.V {
+			t.Errorf("tests[%d] want %s got %s", i, test.V, sql)
+		}
+	}
+}
+
+func TestSimplifyFormat(t *testing.T) {
+	sel := Select().From(T("user")).Where(Or(And(Eq(C("a"), 1), Eq(C("b"), 2)), Eq(C("c"), 3)), Eq(C("d"), 4))
+	sql, _ := Format(Simplify(sel).(Builder))
+	want := `SELECT *
+FROM "user"
+WHERE ("a" = ?
+      AND "b" = ?
+    OR "c" = ?)
+  AND "d" = ?`
+	if sql != want {
+		t.Errorf("want\n%s\ngot\n%s", want, sql)
+	}
+}
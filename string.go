@@ -0,0 +1,162 @@
+package q
+
+import "github.com/oov/q/qutil"
+
+// Lower creates Function such as "LOWER(v)".
+func Lower(v interface{}) Function {
+	return &variadicFunc{"LOWER", []interface{}{v}}
+}
+
+// Upper creates Function such as "UPPER(v)".
+func Upper(v interface{}) Function {
+	return &variadicFunc{"UPPER", []interface{}{v}}
+}
+
+// Trim creates Function such as "TRIM(v)", which removes the spaces from both ends of v.
+func Trim(v interface{}) Function {
+	return &variadicFunc{"TRIM", []interface{}{v}}
+}
+
+// LTrim creates Function such as "LTRIM(v)", which removes the leading spaces of v.
+func LTrim(v interface{}) Function {
+	return &variadicFunc{"LTRIM", []interface{}{v}}
+}
+
+// RTrim creates Function such as "RTRIM(v)", which removes the trailing spaces of v.
+func RTrim(v interface{}) Function {
+	return &variadicFunc{"RTRIM", []interface{}{v}}
+}
+
+// Replace creates Function such as "REPLACE(v, from, to)", which replaces all from in v with to.
+func Replace(v, from, to interface{}) Function {
+	return &variadicFunc{"REPLACE", []interface{}{v, from, to}}
+}
+
+type stringOp int
+
+const (
+	opSubstring stringOp = iota
+	opPosition
+	opByteLength
+	opLeft
+	opRight
+	opLPad
+	opRPad
+	opRepeat
+)
+
+type stringFunc struct {
+	Op     stringOp
+	Values []interface{}
+}
+
+func (f *stringFunc) String() string               { return expressionToString(f) }
+func (f *stringFunc) C(aliasName ...string) Column { return columnExpr(f, aliasName...) }
+func (f *stringFunc) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	// The dialects which emulate the functions with the operators
+	// enclose the operator expressions in parentheses.
+	vs := make([]interface{}, len(f.Values))
+	for i, v := range f.Values {
+		if operatorPrecedence(v) != 0 {
+			v = operandExpr{v}
+		}
+		vs[i] = v
+	}
+	d := ctx.Dialect
+	switch f.Op {
+	case opSubstring:
+		return writeFunc(ctx, buf, d.SubstringName(), vs)
+	case opPosition:
+		return writeFunc(ctx, buf, d.PositionName(), vs)
+	case opByteLength:
+		return d.ByteLength(ctx, buf, vs[0])
+	case opLeft:
+		return d.Left(ctx, buf, vs[0], vs[1])
+	case opRight:
+		return d.Right(ctx, buf, vs[0], vs[1])
+	case opLPad:
+		return d.LPad(ctx, buf, vs[0], vs[1], vs[2])
+	case opRPad:
+		return d.RPad(ctx, buf, vs[0], vs[1], vs[2])
+	case opRepeat:
+		return d.Repeat(ctx, buf, vs[0], vs[1])
+	}
+	panic("q: unknown string function.")
+}
+
+// operandExpr is written as is in the function arguments,
+// and is enclosed in parentheses if needed when the dialect uses it as an operand of the operators.
+type operandExpr struct {
+	V interface{}
+}
+
+func (e operandExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	return writeIntf(e.V, ctx, buf)
+}
+
+func (e operandExpr) WriteOperand(ctx *qutil.Context, buf []byte) []byte {
+	return writeIntf(operand(e.V, precUnary, false), ctx, buf)
+}
+
+// Substring creates Function such as "SUBSTRING(v, pos, length)",
+// which returns length characters from the 1-based position pos of v.
+// If length is omitted, the rest of v is returned.
+// In PostgreSQL and SQLite, it is written as "SUBSTR(v, pos, length)",
+// because "SUBSTRING(v, pattern)" in PostgreSQL may be resolved as the regular expression.
+func Substring(v, pos interface{}, length ...interface{}) Function {
+	switch len(length) {
+	case 0:
+		return &stringFunc{opSubstring, []interface{}{v, pos}}
+	case 1:
+		return &stringFunc{opSubstring, []interface{}{v, pos, length[0]}}
+	}
+	panic("q: too many arguments for SUBSTRING.")
+}
+
+// Position creates Function which returns the 1-based position of the first substr in v,
+// or 0 if v doesn't contain substr.
+// It is written as "INSTR(v, substr)" in MySQL and SQLite, and "STRPOS(v, substr)" in PostgreSQL.
+func Position(v, substr interface{}) Function {
+	return &stringFunc{opPosition, []interface{}{v, substr}}
+}
+
+// ByteLength creates Function which returns the length of v in bytes,
+// such as "OCTET_LENGTH(v)" in PostgreSQL.
+// It is written as "LENGTH(v)" in MySQL and "LENGTH(CAST(v AS BLOB))" in SQLite.
+func ByteLength(v interface{}) Function {
+	return &stringFunc{opByteLength, []interface{}{v}}
+}
+
+// Left creates Function such as "LEFT(v, n)", which returns the first n characters of v.
+// In SQLite, it is written as "SUBSTR(v, 1, n)".
+func Left(v, n interface{}) Function {
+	return &stringFunc{opLeft, []interface{}{v, n}}
+}
+
+// Right creates Function such as "RIGHT(v, n)", which returns the last n characters of v.
+// In SQLite, it is written as "SUBSTR(v, -n, n)".
+func Right(v, n interface{}) Function {
+	return &stringFunc{opRight, []interface{}{v, n}}
+}
+
+// LPad creates Function such as "LPAD(v, n, pad)",
+// which prepends pad to v until the length becomes n.
+// v is truncated to n characters if it is longer than n.
+// SQLite has no LPAD, so it is emulated with SUBSTR and ZEROBLOB.
+func LPad(v, n, pad interface{}) Function {
+	return &stringFunc{opLPad, []interface{}{v, n, pad}}
+}
+
+// RPad creates Function such as "RPAD(v, n, pad)",
+// which appends pad to v until the length becomes n.
+// v is truncated to n characters if it is longer than n.
+// SQLite has no RPAD, so it is emulated with SUBSTR and ZEROBLOB.
+func RPad(v, n, pad interface{}) Function {
+	return &stringFunc{opRPad, []interface{}{v, n, pad}}
+}
+
+// Repeat creates Function such as "REPEAT(v, n)", which repeats v n times.
+// SQLite has no REPEAT, so it is written as "REPLACE(HEX(ZEROBLOB(n)), '00', v)".
+func Repeat(v, n interface{}) Function {
+	return &stringFunc{opRepeat, []interface{}{v, n}}
+}
@@ -0,0 +1,153 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestStringFunc(t *testing.T) {
+	x, n := C("x"), C("n")
+	for i, test := range []struct {
+		F                               Function
+		MySQL, PostgreSQL, SQLite, Fake string
+	}{
+		{
+			F:          Lower(x),
+			MySQL:      "LOWER(`x`) []",
+			PostgreSQL: `LOWER("x") []`,
+			SQLite:     `LOWER("x") []`,
+			Fake:       `LOWER("x") []`,
+		},
+		{
+			F:          Upper(x),
+			MySQL:      "UPPER(`x`) []",
+			PostgreSQL: `UPPER("x") []`,
+			SQLite:     `UPPER("x") []`,
+			Fake:       `UPPER("x") []`,
+		},
+		{
+			F:          Trim(x),
+			MySQL:      "TRIM(`x`) []",
+			PostgreSQL: `TRIM("x") []`,
+			SQLite:     `TRIM("x") []`,
+			Fake:       `TRIM("x") []`,
+		},
+		{
+			F:          LTrim(x),
+			MySQL:      "LTRIM(`x`) []",
+			PostgreSQL: `LTRIM("x") []`,
+			SQLite:     `LTRIM("x") []`,
+			Fake:       `LTRIM("x") []`,
+		},
+		{
+			F:          RTrim(x),
+			MySQL:      "RTRIM(`x`) []",
+			PostgreSQL: `RTRIM("x") []`,
+			SQLite:     `RTRIM("x") []`,
+			Fake:       `RTRIM("x") []`,
+		},
+		{
+			F:          Replace(x, "a", "b"),
+			MySQL:      "REPLACE(`x`, ?, ?) [a b]",
+			PostgreSQL: `REPLACE("x", $1, $2) [a b]`,
+			SQLite:     `REPLACE("x", ?, ?) [a b]`,
+			Fake:       `REPLACE("x", ?, ?) [a b]`,
+		},
+		{
+			F:          Substring(x, 2),
+			MySQL:      "SUBSTRING(`x`, ?) [2]",
+			PostgreSQL: `SUBSTR("x", $1) [2]`,
+			SQLite:     `SUBSTR("x", ?) [2]`,
+			Fake:       `SUBSTR("x", ?) [2]`,
+		},
+		{
+			F:          Substring(x, 2, n),
+			MySQL:      "SUBSTRING(`x`, ?, `n`) [2]",
+			PostgreSQL: `SUBSTR("x", $1, "n") [2]`,
+			SQLite:     `SUBSTR("x", ?, "n") [2]`,
+			Fake:       `SUBSTR("x", ?, "n") [2]`,
+		},
+		{
+			F:          Position(x, "@"),
+			MySQL:      "INSTR(`x`, ?) [@]",
+			PostgreSQL: `STRPOS("x", $1) [@]`,
+			SQLite:     `INSTR("x", ?) [@]`,
+			Fake:       `STRPOS("x", ?) [@]`,
+		},
+		{
+			F:          ByteLength(x),
+			MySQL:      "LENGTH(`x`) []",
+			PostgreSQL: `OCTET_LENGTH("x") []`,
+			SQLite:     `LENGTH(CAST("x" AS BLOB)) []`,
+			Fake:       `OCTET_LENGTH("x") []`,
+		},
+		{
+			F:          Left(x, 3),
+			MySQL:      "LEFT(`x`, ?) [3]",
+			PostgreSQL: `LEFT("x", $1) [3]`,
+			SQLite:     `SUBSTR("x", 1, ?) [3]`,
+			Fake:       `LEFT("x", ?) [3]`,
+		},
+		{
+			F:          Right(x, Add(n, 1)),
+			MySQL:      "RIGHT(`x`, `n` + ?) [1]",
+			PostgreSQL: `RIGHT("x", "n" + $1) [1]`,
+			SQLite:     `SUBSTR("x", -("n" + ?), "n" + ?) [1 1]`,
+			Fake:       `RIGHT("x", "n" + ?) [1]`,
+		},
+		{
+			F:          LPad(x, 5, "0"),
+			MySQL:      "LPAD(`x`, ?, ?) [5 0]",
+			PostgreSQL: `LPAD("x", $1, $2) [5 0]`,
+			SQLite:     `SUBSTR(SUBSTR(REPLACE(HEX(ZEROBLOB(?)), '00', ?), 1, MAX(? - LENGTH("x"), 0)) || "x", 1, ?) [5 0 5 5]`,
+			Fake:       `LPAD("x", ?, ?) [5 0]`,
+		},
+		{
+			F:          RPad(Concat(x, n), 5, "-"),
+			MySQL:      "RPAD(CONCAT(`x`, `n`), ?, ?) [5 -]",
+			PostgreSQL: `RPAD("x" || "n", $1, $2) [5 -]`,
+			SQLite:     `SUBSTR(("x" || "n") || REPLACE(HEX(ZEROBLOB(?)), '00', ?), 1, ?) [5 - 5]`,
+			Fake:       `RPAD("x" || "n", ?, ?) [5 -]`,
+		},
+		{
+			F:          LPad(Concat(x, n), Sub(n, 1), "0"),
+			MySQL:      "LPAD(CONCAT(`x`, `n`), `n` - ?, ?) [1 0]",
+			PostgreSQL: `LPAD("x" || "n", "n" - $1, $2) [1 0]`,
+			SQLite:     `SUBSTR(SUBSTR(REPLACE(HEX(ZEROBLOB("n" - ?)), '00', ?), 1, MAX(("n" - ?) - LENGTH("x" || "n"), 0)) || ("x" || "n"), 1, "n" - ?) [1 0 1 1]`,
+			Fake:       `LPAD("x" || "n", "n" - ?, ?) [1 0]`,
+		},
+		{
+			F:          Repeat(x, n),
+			MySQL:      "REPEAT(`x`, `n`) []",
+			PostgreSQL: `REPEAT("x", "n") []`,
+			SQLite:     `REPLACE(HEX(ZEROBLOB("n")), '00', "x") []`,
+			Fake:       `REPEAT("x", "n") []`,
+		},
+	} {
+		for _, d := range []struct {
+			D    qutil.Dialect
+			Want string
+		}{
+			{MySQL, test.MySQL}, {PostgreSQL, test.PostgreSQL}, {SQLite, test.SQLite}, {nil, test.Fake},
+		} {
+			sql, args := Select().Column(test.F.C()).SetDialect(d.D).ToSQL()
+			if got, want := fmt.Sprint(sql, " ", args), "SELECT "+d.Want; got != want {
+				t.Errorf("tests[%d] %v want %s got %s", i, d.D, want, got)
+			}
+		}
+		if got, want := fmt.Sprint(clone(test.F)), fmt.Sprint(test.F); got != want {
+			t.Errorf("tests[%d] clone want %s got %s", i, want, got)
+		}
+	}
+}
+
+func TestSubstringPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("want panic")
+		}
+	}()
+	Substring(C("x"), 1, 2, 3)
+}
@@ -4,9 +4,13 @@ import "github.com/oov/q/qutil"
 
 // Table represents database table.
 // You can create it from T or *ZSelectBuilder.T.
+//
+// Table is immutable. InnerJoin, LeftJoin and CrossJoin return a new Table
+// and never modify the receiver, so Table can be shared between goroutines.
 type Table interface {
 	C(columnName string, aliasName ...string) Column
 
+	// InnerJoin, LeftJoin and CrossJoin return a new Table which has the join.
 	InnerJoin(table Table, conds ...Expression) Table
 	LeftJoin(table Table, conds ...Expression) Table
 	CrossJoin(table Table) Table
@@ -36,24 +40,32 @@ type joinable struct {
 	Joins []join
 }
 
-func (j *joinable) InnerJoin(table Table, conds ...Expression) {
-	j.Joins = append(j.Joins, join{
+// add returns a copy of j which has jn in addition.
+// The receiver's slice is never appended in place, so the receiver can be shared.
+func (j *joinable) add(jn join) joinable {
+	joins := make([]join, len(j.Joins), len(j.Joins)+1)
+	copy(joins, j.Joins)
+	return joinable{Joins: append(joins, jn)}
+}
+
+func (j *joinable) InnerJoin(table Table, conds ...Expression) joinable {
+	return j.add(join{
 		Type:  "INNER",
 		Table: table,
 		Conds: ZAndExpr(conds),
 	})
 }
 
-func (j *joinable) LeftJoin(table Table, conds ...Expression) {
-	j.Joins = append(j.Joins, join{
+func (j *joinable) LeftJoin(table Table, conds ...Expression) joinable {
+	return j.add(join{
 		Type:  "LEFT",
 		Table: table,
 		Conds: ZAndExpr(conds),
 	})
 }
 
-func (j *joinable) CrossJoin(table Table) {
-	j.Joins = append(j.Joins, join{
+func (j *joinable) CrossJoin(table Table) joinable {
+	return j.add(join{
 		Type:  "CROSS",
 		Table: table,
 	})
@@ -70,7 +82,9 @@ func (j *joinable) JoinLen() int {
 
 func (j *joinable) WriteJoins(ctx *qutil.Context, buf []byte) []byte {
 	for _, v := range j.Joins {
-		buf = append(buf, ' ')
+		ctx.Indent++
+		buf = writeSeparator(ctx, buf)
+		ctx.Indent--
 		buf = append(buf, v.Type...)
 		buf = append(buf, " JOIN "...)
 		hasJoins := v.Table.JoinLen() > 0
@@ -132,18 +146,15 @@ func (t *tableAlias) WriteDefinition(ctx *qutil.Context, buf []byte) []byte {
 }
 
 func (t *tableAlias) InnerJoin(table Table, conds ...Expression) Table {
-	t.Table.InnerJoin(table, conds...)
-	return t
+	return &tableAlias{Table: t.Table.InnerJoin(table, conds...), Alias: t.Alias}
 }
 
 func (t *tableAlias) LeftJoin(table Table, conds ...Expression) Table {
-	t.Table.LeftJoin(table, conds...)
-	return t
+	return &tableAlias{Table: t.Table.LeftJoin(table, conds...), Alias: t.Alias}
 }
 
 func (t *tableAlias) CrossJoin(table Table) Table {
-	t.Table.CrossJoin(table)
-	return t
+	return &tableAlias{Table: t.Table.CrossJoin(table), Alias: t.Alias}
 }
 
 type table struct {
@@ -170,18 +181,21 @@ func (t *table) C(columnName string, aliasName ...string) Column {
 }
 
 func (t *table) InnerJoin(table Table, conds ...Expression) Table {
-	t.joinable.InnerJoin(table, conds...)
-	return t
+	r := *t
+	r.joinable = t.joinable.InnerJoin(table, conds...)
+	return &r
 }
 
 func (t *table) LeftJoin(table Table, conds ...Expression) Table {
-	t.joinable.LeftJoin(table, conds...)
-	return t
+	r := *t
+	r.joinable = t.joinable.LeftJoin(table, conds...)
+	return &r
 }
 
 func (t *table) CrossJoin(table Table) Table {
-	t.joinable.CrossJoin(table)
-	return t
+	r := *t
+	r.joinable = t.joinable.CrossJoin(table)
+	return &r
 }
 
 type selectBuilderAsTable struct {
@@ -199,9 +213,8 @@ func (t *selectBuilderAsTable) WriteTable(ctx *qutil.Context, buf []byte) []byte
 }
 
 func (t *selectBuilderAsTable) WriteDefinition(ctx *qutil.Context, buf []byte) []byte {
-	buf = append(buf, '(')
-	buf = t.ZSelectBuilder.write(ctx, buf)
-	buf = append(buf, ") AS "...)
+	buf = writeSubquery(t.ZSelectBuilder, ctx, buf)
+	buf = append(buf, " AS "...)
 	buf = t.WriteTable(ctx, buf)
 	buf = t.WriteJoins(ctx, buf)
 	return buf
@@ -212,18 +225,21 @@ func (t *selectBuilderAsTable) C(columnName string, aliasName ...string) Column
 }
 
 func (t *selectBuilderAsTable) InnerJoin(table Table, conds ...Expression) Table {
-	t.joinable.InnerJoin(table, conds...)
-	return t
+	r := *t
+	r.joinable = t.joinable.InnerJoin(table, conds...)
+	return &r
 }
 
 func (t *selectBuilderAsTable) LeftJoin(table Table, conds ...Expression) Table {
-	t.joinable.LeftJoin(table, conds...)
-	return t
+	r := *t
+	r.joinable = t.joinable.LeftJoin(table, conds...)
+	return &r
 }
 
 func (t *selectBuilderAsTable) CrossJoin(table Table) Table {
-	t.joinable.CrossJoin(table)
-	return t
+	r := *t
+	r.joinable = t.joinable.CrossJoin(table)
+	return &r
 }
 
 // T creates Table.
@@ -3,6 +3,7 @@ package q
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/oov/q/qutil"
@@ -270,6 +271,50 @@ func TestTable(t *testing.T) {
 	}
 }
 
+var sharedUser = T("user", "u")
+
+func TestTableImmutable(t *testing.T) {
+	for _, tbl := range []Table{T("user"), sharedUser, Select().From(T("user")).T("s")} {
+		want := fmt.Sprint(tbl)
+		j1 := tbl.InnerJoin(T("post"), Eq(1, 1))
+		j2 := j1.LeftJoin(T("tag"))
+		j1.CrossJoin(T("posttag"))
+		if r := fmt.Sprint(tbl); r != want {
+			t.Errorf("the original table was modified: want %s got %s", want, r)
+		}
+		if j1.JoinLen() != 1 || j2.JoinLen() != 2 {
+			t.Errorf("want 1 and 2 joins got %d and %d", j1.JoinLen(), j2.JoinLen())
+		}
+	}
+}
+
+func TestTableJoinConcurrent(t *testing.T) {
+	const n = 16
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			post := T("post", "p")
+			sel := Select().From(
+				sharedUser.InnerJoin(post, Eq(post.C("user_id"), sharedUser.C("id"))),
+			).Column(sharedUser.C("name").C("n")).Where(Eq(post.C("id"), i))
+			results[i], _ = sel.ToSQL()
+		}(i)
+	}
+	wg.Wait()
+	want := `SELECT "u"."name" AS "n" FROM "user" AS "u" INNER JOIN "post" AS "p" ON "p"."user_id" = "u"."id" WHERE "p"."id" = ?`
+	for i, r := range results {
+		if r != want {
+			t.Errorf("results[%d] want %s got %s", i, want, r)
+		}
+	}
+	if r := fmt.Sprint(sharedUser); r != `"user" AS "u" []` {
+		t.Errorf("the shared table was modified: %s", r)
+	}
+}
+
 func TestTableOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
@@ -0,0 +1,251 @@
+package q
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/oov/q/qutil"
+)
+
+// Tuple creates Expression which represents the row value such as "(v[0], v[1])".
+//
+// It can be passed to l of Eq, Neq, Gt, Gte, Lt, Lte, In and NotIn
+// to compare the row values such as "(a, b) > (?, ?)".
+// r is Tuple or slice of the same length for the comparison operators,
+// and slice of Tuple or slice of slices such as [][]interface{} for Eq(IN), Neq(NOT IN), In and NotIn.
+// Eq and Neq also accept a flat slice such as []interface{}{1, 2}, which is compared as a single row value.
+// In and NotIn accept a flat slice too, which is split into the row values by the number of values of the tuple,
+// such as "(a, b) IN ((1, 2), (3, 4))" for []interface{}{1, 2, 3, 4}.
+// If the tuples have the different number of values, the error is reported by Validate.
+// In the dialect which doesn't support the row values, such as SQLite,
+// the comparisons are expanded into the equivalent AND/OR forms such as "(a > ?)OR((a = ?)AND(b > ?))".
+func Tuple(v ...interface{}) Expression {
+	if len(v) == 0 {
+		panic("q: need at least one value to create a tuple.")
+	}
+	return &tupleExpr{Values: v}
+}
+
+type tupleExpr struct {
+	Values []interface{}
+}
+
+func (e *tupleExpr) String() string               { return expressionToString(e) }
+func (e *tupleExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *tupleExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, '(')
+	for i, v := range e.Values {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = writeIntf(v, ctx, buf)
+	}
+	return append(buf, ')')
+}
+
+func sliceToTuple(rv reflect.Value) *tupleExpr {
+	return &tupleExpr{Values: valueToInV(rv)}
+}
+
+// toTuple converts x to the tuple if x is Tuple or slice.
+func toTuple(x interface{}) (*tupleExpr, bool) {
+	if t, ok := x.(*tupleExpr); ok {
+		return t, true
+	}
+	if _, ok := x.(Expression); ok {
+		return nil, false
+	}
+	if rv := reflect.ValueOf(x); rv.Kind() == reflect.Slice {
+		return sliceToTuple(rv), true
+	}
+	return nil, false
+}
+
+// rowValue returns the row value comparison if l is Tuple.
+func rowValue(l interface{}, op string, r interface{}) (Expression, bool) {
+	t, ok := l.(*tupleExpr)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := r.(Expression); !ok {
+		if rv := reflect.ValueOf(r); rv.Kind() == reflect.Slice {
+			switch op {
+			case "=", "!=":
+				if isFlatSlice(rv) {
+					return &rowCompareExpr{Op: op, Left: t, Right: sliceToTuple(rv)}, true
+				}
+			}
+			switch op {
+			case "=", "IN":
+				return &tupleInExpr{Left: t, Right: slicesToTuples(rv, len(t.Values))}, true
+			case "!=", "NOT IN":
+				return &tupleInExpr{Left: t, Right: slicesToTuples(rv, len(t.Values)), Not: true}, true
+			}
+			return &rowCompareExpr{Op: op, Left: t, Right: sliceToTuple(rv)}, true
+		}
+	}
+	switch op {
+	case "IN", "NOT IN":
+		// such as "(a, b) IN (SELECT ...)"
+		return nil, false
+	}
+	return &rowCompareExpr{Op: op, Left: t, Right: r}, true
+}
+
+// isFlatSlice reports whether rv is not empty and none of its elements is Tuple or slice.
+func isFlatSlice(rv reflect.Value) bool {
+	if rv.Len() == 0 {
+		return false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if _, ok := toTuple(rv.Index(i).Interface()); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// slicesToTuples converts the elements of rv to the tuples.
+// If rv is a flat slice, it is split into the tuples which have n values.
+// The element which is not Tuple or slice becomes the tuple which has only the element,
+// so the length mismatch is reported by Validate instead of panic.
+func slicesToTuples(rv reflect.Value, n int) []*tupleExpr {
+	if isFlatSlice(rv) {
+		vs := valueToInV(rv)
+		r := make([]*tupleExpr, 0, (len(vs)+n-1)/n)
+		for len(vs) > n {
+			r = append(r, &tupleExpr{Values: vs[:n:n]})
+			vs = vs[n:]
+		}
+		return append(r, &tupleExpr{Values: vs})
+	}
+	r := make([]*tupleExpr, rv.Len())
+	for i := range r {
+		v := rv.Index(i).Interface()
+		t, ok := toTuple(v)
+		if !ok {
+			t = &tupleExpr{Values: []interface{}{v}}
+		}
+		r[i] = t
+	}
+	return r
+}
+
+// sameLength records an error to ctx if l and r have the different number of values.
+func sameLength(ctx *qutil.Context, l, r *tupleExpr) bool {
+	if len(l.Values) == len(r.Values) {
+		return true
+	}
+	setError(ctx, fmt.Errorf("q: the tuples to compare must have the same number of values, got %d and %d", len(l.Values), len(r.Values)))
+	return false
+}
+
+// rowCompare creates Expression which compares the each values of l and r by op
+// such as "(l[0] = r[0])AND(l[1] = r[1])".
+// l and r must have the same number of values.
+func rowCompare(op string, l, r *tupleExpr) Expression {
+	cmp := func(op string, i int) Expression {
+		return unsafeExpr{interfaceToExpression(l.Values[i]), " " + op + " ", interfaceToExpression(r.Values[i])}
+	}
+	switch op {
+	case "=", "!=":
+		exprs := make([]Expression, len(l.Values))
+		for i := range exprs {
+			exprs[i] = cmp(op, i)
+		}
+		if op == "=" {
+			return ZAndExpr(exprs)
+		}
+		return ZOrExpr(exprs)
+	}
+
+	// The lexicographical order such as "(a, b) > (x, y)" is expanded into
+	// "(a > x)OR((a = x)AND(b > y))".
+	strict := op[:1]
+	var ors ZOrExpr
+	for i := range l.Values {
+		ands := make(ZAndExpr, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, cmp("=", j))
+		}
+		if i == len(l.Values)-1 {
+			ands = append(ands, cmp(op, i))
+		} else {
+			ands = append(ands, cmp(strict, i))
+		}
+		ors = append(ors, ands)
+	}
+	return ors
+}
+
+type rowCompareExpr struct {
+	Op    string
+	Left  *tupleExpr
+	Right interface{}
+}
+
+func (e *rowCompareExpr) String() string               { return expressionToString(e) }
+func (e *rowCompareExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *rowCompareExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if r, ok := e.Right.(*tupleExpr); ok && sameLength(ctx, e.Left, r) && !ctx.Dialect.CanUseRowValue() {
+		return rowCompare(e.Op, e.Left, r).WriteExpression(ctx, buf)
+	}
+	buf = e.Left.WriteExpression(ctx, buf)
+	buf = append(buf, ' ')
+	buf = append(buf, e.Op...)
+	buf = append(buf, ' ')
+	return writeIntf(e.Right, ctx, buf)
+}
+
+type tupleInExpr struct {
+	Left  *tupleExpr
+	Right []*tupleExpr
+	Not   bool
+}
+
+func (e *tupleInExpr) String() string               { return expressionToString(e) }
+func (e *tupleInExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *tupleInExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	if len(e.Right) == 0 {
+		// x IN () is invalid syntax, but its result is obvious.
+		return append(buf, ctx.Dialect.BoolLiteral(e.Not)...)
+	}
+	valid := true
+	for _, t := range e.Right {
+		valid = sameLength(ctx, e.Left, t) && valid
+	}
+	if valid && !ctx.Dialect.CanUseRowValue() {
+		exprs := make([]Expression, len(e.Right))
+		if e.Not {
+			for i, t := range e.Right {
+				exprs[i] = rowCompare("!=", e.Left, t)
+			}
+			return ZAndExpr(exprs).WriteExpression(ctx, buf)
+		}
+		for i, t := range e.Right {
+			exprs[i] = rowCompare("=", e.Left, t)
+		}
+		return ZOrExpr(exprs).WriteExpression(ctx, buf)
+	}
+
+	buf = e.Left.WriteExpression(ctx, buf)
+	if e.Not {
+		buf = append(buf, " NOT IN "...)
+	} else {
+		buf = append(buf, " IN "...)
+	}
+	if ctx.Normalize {
+		for _, t := range e.Right {
+			_ = t.WriteExpression(ctx, nil)
+		}
+		return append(buf, "(...)"...)
+	}
+	buf = append(buf, '(')
+	for i, t := range e.Right {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = t.WriteExpression(ctx, buf)
+	}
+	return append(buf, ')')
+}
@@ -0,0 +1,205 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+var tupleTests = []struct {
+	Name string
+	E    Expression
+	D    qutil.Dialect
+	SQL  string
+	Args string
+}{
+	{
+		Name: "eq",
+		E:    Eq(Tuple(C("a"), C("b")), Tuple(1, 2)),
+		D:    PostgreSQL,
+		SQL:  `("a", "b") = ($1, $2)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "gt with slice",
+		E:    Gt(Tuple(C("a"), C("b")), []interface{}{1, "x"}),
+		D:    MySQL,
+		SQL:  "(`a`, `b`) > (?, ?)",
+		Args: "[1 x]",
+	},
+	{
+		Name: "eq with slice",
+		E:    Eq(Tuple(C("a"), C("b")), []interface{}{1, 2}),
+		D:    PostgreSQL,
+		SQL:  `("a", "b") = ($1, $2)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "in",
+		E:    In(Tuple(C("a"), C("b")), [][]interface{}{{1, 2}, {3, 4}}),
+		D:    PostgreSQL,
+		SQL:  `("a", "b") IN (($1, $2), ($3, $4))`,
+		Args: "[1 2 3 4]",
+	},
+	{
+		Name: "in with flat slice",
+		E:    In(Tuple(C("a"), C("b")), []interface{}{1, 2, 3, 4}),
+		D:    PostgreSQL,
+		SQL:  `("a", "b") IN (($1, $2), ($3, $4))`,
+		Args: "[1 2 3 4]",
+	},
+	{
+		Name: "emulated not in with flat slice",
+		E:    NotIn(Tuple(C("a"), C("b")), []int{1, 2}),
+		D:    SQLite,
+		SQL:  `("a" != ?)OR("b" != ?)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "eq with list",
+		E:    Eq(Tuple(C("a"), C("b")), []Expression{Tuple(1, 2), Tuple(C("c"), 4)}),
+		D:    MySQL,
+		SQL:  "(`a`, `b`) IN ((?, ?), (`c`, ?))",
+		Args: "[1 2 4]",
+	},
+	{
+		Name: "not in",
+		E:    NotIn(Tuple(C("a"), C("b")), [][]int{{1, 2}}),
+		D:    MySQL,
+		SQL:  "(`a`, `b`) NOT IN ((?, ?))",
+		Args: "[1 2]",
+	},
+	{
+		Name: "in subquery",
+		E:    In(Tuple(C("a"), C("b")), Select().Column(C("x"), C("y")).From(T("t"))),
+		D:    SQLite,
+		SQL:  `("a", "b") IN (SELECT "x", "y" FROM "t")`,
+		Args: "[]",
+	},
+	{
+		Name: "empty in",
+		E:    In(Tuple(C("a"), C("b")), [][]int{}),
+		D:    PostgreSQL,
+		SQL:  `FALSE`,
+		Args: "[]",
+	},
+	{
+		Name: "emulated eq",
+		E:    Eq(Tuple(C("a"), C("b")), Tuple(1, nil)),
+		D:    SQLite,
+		SQL:  `("a" = ?)AND("b" = NULL)`,
+		Args: "[1]",
+	},
+	{
+		Name: "emulated neq",
+		E:    Neq(Tuple(C("a"), C("b")), Tuple(1, 2)),
+		D:    SQLite,
+		SQL:  `("a" != ?)OR("b" != ?)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "emulated neq with slice",
+		E:    Neq(Tuple(C("a"), C("b")), []int{1, 2}),
+		D:    SQLite,
+		SQL:  `("a" != ?)OR("b" != ?)`,
+		Args: "[1 2]",
+	},
+	{
+		Name: "emulated gt",
+		E:    Gt(Tuple(C("a"), C("b"), C("c")), Tuple(1, 2, 3)),
+		D:    SQLite,
+		SQL:  `("a" > ?)OR(("a" = ?)AND("b" > ?))OR(("a" = ?)AND("b" = ?)AND("c" > ?))`,
+		Args: "[1 1 2 1 2 3]",
+	},
+	{
+		Name: "emulated gte",
+		E:    Gte(Tuple(C("a"), C("b")), Tuple(1, 2)),
+		D:    SQLite,
+		SQL:  `("a" > ?)OR(("a" = ?)AND("b" >= ?))`,
+		Args: "[1 1 2]",
+	},
+	{
+		Name: "emulated lte",
+		E:    Lte(Tuple(C("a")), Tuple(1)),
+		D:    SQLite,
+		SQL:  `"a" <= ?`,
+		Args: "[1]",
+	},
+	{
+		Name: "emulated in",
+		E:    In(Tuple(C("a"), C("b")), [][]int{{1, 2}, {3, 4}}),
+		D:    SQLite,
+		SQL:  `(("a" = ?)AND("b" = ?))OR(("a" = ?)AND("b" = ?))`,
+		Args: "[1 2 3 4]",
+	},
+	{
+		Name: "emulated not in",
+		E:    Neq(Tuple(C("a"), C("b")), [][]int{{1, 2}, {3, 4}}),
+		D:    SQLite,
+		SQL:  `(("a" != ?)OR("b" != ?))AND(("a" != ?)OR("b" != ?))`,
+		Args: "[1 2 3 4]",
+	},
+	{
+		Name: "emulated empty not in",
+		E:    NotIn(Tuple(C("a"), C("b")), [][]int{}),
+		D:    SQLite,
+		SQL:  `1=1`,
+		Args: "[]",
+	},
+}
+
+func TestTuple(t *testing.T) {
+	for i, test := range tupleTests {
+		buf, ctx := qutil.NewContext(test.E, 32, 1, test.D)
+		buf = test.E.WriteExpression(ctx, buf)
+		if got := string(buf); got != test.SQL {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.SQL, got)
+		}
+		if got := fmt.Sprint(ctx.Args); got != test.Args {
+			t.Errorf("tests[%d] %s want %s got %s", i, test.Name, test.Args, got)
+		}
+	}
+}
+
+func TestTuplePanic(t *testing.T) {
+	for i, f := range []func(){
+		func() { Tuple() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("tests[%d] want panic", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestTupleValidate(t *testing.T) {
+	for i, e := range []Expression{
+		Gt(Tuple(C("a"), C("b")), Tuple(1)),
+		Eq(Tuple(C("a"), C("b")), []int{1, 2, 3}),
+		In(Tuple(C("a"), C("b")), [][]int{{1, 2}, {3}}),
+		In(Tuple(C("a"), C("b")), []int{1, 2, 3}),
+		NotIn(Tuple(C("a"), C("b")), []interface{}{[]int{1, 2}, 3}),
+	} {
+		for _, d := range []qutil.Dialect{MySQL, PostgreSQL, SQLite} {
+			if err := Validate(Select().From(T("t")).Where(e).SetDialect(d)); err == nil {
+				t.Errorf("tests[%d] %v want error", i, d)
+			}
+		}
+	}
+	if err := Validate(Select().From(T("t")).Where(Eq(Tuple(C("a"), C("b")), []int{1, 2})).SetDialect(SQLite)); err != nil {
+		t.Errorf("want nil got %v", err)
+	}
+}
+
+func TestTupleFingerprint(t *testing.T) {
+	a, _ := Fingerprint(Select().From(T("t")).Where(In(Tuple(C("a"), C("b")), [][]int{{1, 2}})))
+	b, _ := Fingerprint(Select().From(T("t")).Where(In(Tuple(C("a"), C("b")), [][]int{{1, 2}, {3, 4}})))
+	if a != b {
+		t.Errorf("want same fingerprints got %s and %s", a, b)
+	}
+}
@@ -38,10 +38,13 @@ func (b *ZUpdateBuilder) SetDialect(d qutil.Dialect) *ZUpdateBuilder {
 	return b
 }
 
+// Clone returns a deep copy of the builder.
+func (b *ZUpdateBuilder) Clone() *ZUpdateBuilder {
+	return clone(b).(*ZUpdateBuilder)
+}
+
 func (b *ZUpdateBuilder) find(c Column) (int, string) {
-	buf, ctx := qutil.NewContext(b, 32, 0, nil)
-	ctx.CUD = true
-	name := string(c.WriteColumn(ctx, buf))
+	name := cudColumnName(c)
 	for i, s := range b.Sets {
 		if name == s.Name {
 			return i, name
@@ -103,7 +106,8 @@ func (b *ZUpdateBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 
 	buf = b.Table.WriteTable(ctx, buf)
 
-	buf = append(buf, " SET "...)
+	buf = writeSeparator(ctx, buf)
+	buf = append(buf, "SET "...)
 	for i, s := range b.Sets {
 		if i > 0 {
 			buf = append(buf, ", "...)
@@ -114,7 +118,8 @@ func (b *ZUpdateBuilder) write(ctx *qutil.Context, buf []byte) []byte {
 	}
 
 	if len(b.Wheres) > 0 {
-		buf = append(buf, " WHERE "...)
+		buf = writeSeparator(ctx, buf)
+		buf = append(buf, "WHERE "...)
 		buf = b.Wheres.WriteExpression(ctx, buf)
 	}
 	return buf
@@ -173,6 +173,21 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdateClone(t *testing.T) {
+	for i, test := range updateTests {
+		if r := test.B.Clone().String(); r != test.V {
+			t.Errorf("tests[%d] %s: want %q got %q", i, test.Name, test.V, r)
+		}
+	}
+
+	base := Update(T("user")).Set(C("name"), "a")
+	want := base.String()
+	base.Clone().Set(C("name"), "b").Set(C("age"), 18).Where(Eq(C("id"), 1))
+	if got := base.String(); got != want {
+		t.Errorf("the base builder was modified: want %s got %s", want, got)
+	}
+}
+
 func TestUpdateOnDB(t *testing.T) {
 	for _, testData := range testModel {
 		err := testData.tester(func(db *sql.DB, d qutil.Dialect) {
@@ -34,8 +34,91 @@ func builderToString(b builder, d qutil.Dialect, bufCap int, argsCap int, cud bo
 	return toString(buf, ctx.Args)
 }
 
+// writeSeparator writes a space,
+// or writes a line break and indentation if ctx.Format is true.
+func writeSeparator(ctx *qutil.Context, buf []byte) []byte {
+	if !ctx.Format {
+		return append(buf, ' ')
+	}
+	buf = append(buf, '\n')
+	for i := 0; i < ctx.Indent; i++ {
+		buf = append(buf, "  "...)
+	}
+	return buf
+}
+
+// writeLogicalOperator writes op such as "AND" and the opening parenthesis of the next operand.
+func writeLogicalOperator(ctx *qutil.Context, buf []byte, op string) []byte {
+	if !ctx.Format {
+		buf = append(buf, op...)
+		return append(buf, '(')
+	}
+	buf = writeSeparator(ctx, buf)
+	buf = append(buf, op...)
+	return append(buf, " ("...)
+}
+
+// writeSubquery writes SELECT statement which is enclosed in parentheses.
+func writeSubquery(b *ZSelectBuilder, ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, '(')
+	if !ctx.Format {
+		buf = b.write(ctx, buf)
+		return append(buf, ')')
+	}
+	ctx.Indent++
+	buf = writeSeparator(ctx, buf)
+	buf = b.write(ctx, buf)
+	ctx.Indent--
+	buf = writeSeparator(ctx, buf)
+	return append(buf, ')')
+}
+
 func toString(buf []byte, args []interface{}) string {
 	buf = append(buf, ' ')
 	buf = append(buf, fmt.Sprint(args)...)
 	return string(buf)
 }
+
+// Builder represents a statement builder such as *ZSelectBuilder.
+type Builder interface {
+	ToSQL() (string, []interface{})
+	ToPrepared() (string, func() *ZArgsBuilder)
+	String() string
+	builder
+}
+
+// builderOptions returns the dialect and CUD flag which b uses in ToSQL.
+func builderOptions(b Builder) (qutil.Dialect, bool) {
+	switch v := b.(type) {
+	case *ZSelectBuilder:
+		return v.Dialect, false
+	case *ZInsertBuilder:
+		return v.Dialect, true
+	case *ZUpdateBuilder:
+		return v.Dialect, true
+	case *ZDeleteBuilder:
+		return v.Dialect, true
+	}
+	return nil, false
+}
+
+// Validate writes b in the same way as ToSQL and returns the error which occurred,
+// such as the grouping set that the dialect doesn't support.
+// ToSQL can't return errors, so it writes the SQL anyway and the database reports the error.
+func Validate(b Builder) error {
+	d, cud := builderOptions(b)
+	_, ctx := write(b, d, 128, 8, cud)
+	return ctx.Err
+}
+
+// setError records err to ctx if no error has occurred yet.
+func setError(ctx *qutil.Context, err error) {
+	if ctx.Err == nil {
+		ctx.Err = err
+	}
+}
+
+// clone returns a deep copy of n.
+func clone(n interface{}) interface{} {
+	return Transform(n, func(n interface{}) interface{} { return n })
+}
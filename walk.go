@@ -0,0 +1,570 @@
+package q
+
+import (
+	"reflect"
+
+	"github.com/oov/q/qutil"
+)
+
+// Walkable is implemented by a custom Expression, Column or Table which has child nodes.
+// If implemented, Children, Walk and Transform can look into it.
+type Walkable interface {
+	// Children returns the child nodes.
+	Children() []interface{}
+	// WithChildren returns a copy of the node whose child nodes are replaced by children.
+	// children has the same length and order as the result of Children.
+	WithChildren(children []interface{}) interface{}
+}
+
+func exprsToNodes(exprs []Expression) []interface{} {
+	r := make([]interface{}, len(exprs))
+	for i, e := range exprs {
+		r[i] = e
+	}
+	return r
+}
+
+func nodesToExprs(nodes []interface{}) []Expression {
+	r := make([]Expression, len(nodes))
+	for i, n := range nodes {
+		r[i] = nodeToExpr(n)
+	}
+	return r
+}
+
+func nodeToExpr(n interface{}) Expression {
+	if n == nil {
+		return nil
+	}
+	return n.(Expression)
+}
+
+// nodesToSlice returns the slice of typ which has nodes as the elements,
+// or returns nodes as is if they can't be the elements of typ.
+func nodesToSlice(typ reflect.Type, nodes []interface{}) interface{} {
+	r := reflect.MakeSlice(typ, len(nodes), len(nodes))
+	for i, n := range nodes {
+		if n == nil {
+			switch typ.Elem().Kind() {
+			case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map:
+				continue
+			}
+			return nodes
+		}
+		v := reflect.ValueOf(n)
+		if !v.Type().AssignableTo(typ.Elem()) {
+			return nodes
+		}
+		r.Index(i).Set(v)
+	}
+	return r.Interface()
+}
+
+func joinsToNodes(joins []join) []interface{} {
+	r := []interface{}{}
+	for _, j := range joins {
+		r = append(r, j.Table)
+		r = append(r, exprsToNodes(j.Conds)...)
+	}
+	return r
+}
+
+func nodesToJoins(joins []join, nodes []interface{}) []join {
+	if joins == nil {
+		return nil
+	}
+	r := make([]join, len(joins))
+	for i, j := range joins {
+		r[i] = join{Type: j.Type, Table: nodes[0].(Table)}
+		if j.Conds != nil {
+			r[i].Conds = ZAndExpr(nodesToExprs(nodes[1 : 1+len(j.Conds)]))
+		}
+		nodes = nodes[1+len(j.Conds):]
+	}
+	return r
+}
+
+// Children returns the child nodes of n.
+//
+// n is one of Expression, Column, Table or the builders.
+// The child nodes contain the values which are passed to functions such as Eq as is,
+// so it may contain nil and Go values in addition to the above types.
+// Column which belongs to Table doesn't return the Table as a child node,
+// because the Table can refer the Column in its join conditions.
+func Children(n interface{}) []interface{} {
+	switch v := n.(type) {
+	case Walkable:
+		return v.Children()
+	case unsafeExpr:
+		return append([]interface{}(nil), v...)
+	case inVariable:
+		return append([]interface{}(nil), v...)
+	case *variable:
+		return []interface{}{v.V}
+	case *aliasedVariable:
+		return []interface{}{v.V}
+	case *gtExpr:
+		return []interface{}{v.Left, v.Right}
+	case *gteExpr:
+		return []interface{}{v.Left, v.Right}
+	case *ltExpr:
+		return []interface{}{v.Left, v.Right}
+	case *lteExpr:
+		return []interface{}{v.Left, v.Right}
+	case *simpleInExpr:
+		return []interface{}{v.Left, v.Right}
+	case *simpleNotInExpr:
+		return []interface{}{v.Left, v.Right}
+	case *eqExpr:
+		return []interface{}{v.Left, v.Right}
+	case *neqExpr:
+		return []interface{}{v.Left, v.Right}
+	case *inExpr:
+		return []interface{}{v.Left, v.Right}
+	case *notInExpr:
+		return []interface{}{v.Left, v.Right}
+	case ZAndExpr:
+		return exprsToNodes(v)
+	case ZOrExpr:
+		return exprsToNodes(v)
+	case *logicalExpr:
+		return exprsToNodes(v.Exprs)
+	case *notExpr:
+		return []interface{}{v.Expression}
+	case *existsExpr:
+		return []interface{}{v.Select}
+	case *quantifier:
+		return []interface{}{v.V}
+	case *quantifiedExpr:
+		return []interface{}{v.Left, v.Right}
+	case *likeExpr:
+		return []interface{}{v.Left, v.Right}
+	case *betweenExpr:
+		return []interface{}{v.V, v.From, v.To}
+	case *nullSafeEqExpr:
+		return []interface{}{v.Left, v.Right}
+	case *tupleExpr:
+		return append([]interface{}(nil), v.Values...)
+	case *seekExpr:
+		r := make([]interface{}, 0, len(v.Keys)*2)
+		for _, k := range v.Keys {
+			r = append(r, k.Expression, k.Value)
+		}
+		return r
+	case *rowCompareExpr:
+		return []interface{}{v.Left, v.Right}
+	case *tupleInExpr:
+		r := []interface{}{v.Left}
+		for _, t := range v.Right {
+			r = append(r, t)
+		}
+		return r
+	case *distinctExpr:
+		return []interface{}{v.V}
+	case *rowNumberExpr:
+		r := exprsToNodes(v.Partitions)
+		for _, o := range v.Orders {
+			r = append(r, o.Expression)
+		}
+		return r
+	case *groupingSetExpr:
+		var r []interface{}
+		for _, set := range v.Sets {
+			for _, e := range set {
+				r = append(r, e)
+			}
+		}
+		return r
+	case *binaryOpExpr:
+		return []interface{}{v.Left, v.Right}
+	case *unaryOpExpr:
+		return []interface{}{v.V}
+	case *parenExpr:
+		return []interface{}{v.Expression}
+	case operandExpr:
+		return []interface{}{v.V}
+	case *arrayExpr:
+		return valueToInV(reflect.ValueOf(v.V))
+	case *concatExpr:
+		return append([]interface{}(nil), v.Values...)
+	case *function:
+		return []interface{}{v.V}
+	case *charLengthFunc:
+		return []interface{}{v.V}
+	case *addIntervalFunc:
+		r := []interface{}{v.V}
+		for _, iv := range v.Intervals {
+			if e, ok := iv.(*exprInterval); ok {
+				r = append(r, e.Q)
+			}
+		}
+		return r
+	case *castFunc:
+		return []interface{}{v.V}
+	case *dateTruncFunc:
+		return []interface{}{v.V}
+	case *extractFunc:
+		return []interface{}{v.V}
+	case *dateDiffFunc:
+		return []interface{}{v.From, v.To}
+	case *isoFunc:
+		return []interface{}{v.V}
+	case *variadicFunc:
+		return append([]interface{}(nil), v.Values...)
+	case *stringFunc:
+		return append([]interface{}(nil), v.Values...)
+	case *greatestFunc:
+		return append([]interface{}(nil), v.Values...)
+	case *ifFunc:
+		return []interface{}{v.Cond, v.Then, v.Else}
+	case *ZCaseBuilder:
+		r := []interface{}{v.Base, v.ElseThen}
+		for _, wt := range v.WhenThen {
+			r = append(r, wt[0], wt[1])
+		}
+		return r
+	case *columnAlias:
+		return []interface{}{v.Column}
+	case *exprAsColumn:
+		return []interface{}{v.Expression}
+	case *table:
+		return joinsToNodes(v.Joins)
+	case *tableAlias:
+		return []interface{}{v.Table}
+	case *selectBuilderAsTable:
+		return append([]interface{}{v.ZSelectBuilder}, joinsToNodes(v.Joins)...)
+	case *ZSelectBuilder:
+		r := exprsToNodes(v.DistinctOns)
+		for _, c := range v.Columns {
+			r = append(r, c)
+		}
+		for _, t := range v.Tables {
+			r = append(r, t)
+		}
+		r = append(r, exprsToNodes(v.Wheres)...)
+		r = append(r, exprsToNodes(v.Groups)...)
+		r = append(r, exprsToNodes(v.Havings)...)
+		for _, o := range v.Orders {
+			r = append(r, o.Expression)
+		}
+		return append(r, v.LimitCount, v.StartOffset)
+	case *ZInsertBuilder:
+		r := []interface{}{v.Table}
+		for _, s := range v.Sets {
+			r = append(r, s.Column, s.Expression)
+		}
+		for _, c := range v.Returnings {
+			r = append(r, c)
+		}
+		return r
+	case *ZUpdateBuilder:
+		r := []interface{}{v.Table}
+		for _, s := range v.Sets {
+			r = append(r, s.Column, s.Expression)
+		}
+		return append(r, exprsToNodes(v.Wheres)...)
+	case *ZDeleteBuilder:
+		return append([]interface{}{v.Table}, exprsToNodes(v.Wheres)...)
+	case column, *columnWithTable, *allColumns, nullExpr, boolExpr, stringLiteral, *currentFunc:
+		// They have no child nodes.
+		return nil
+	}
+	return nil
+}
+
+// withChildren returns a copy of n whose child nodes are replaced by cs.
+func withChildren(n interface{}, cs []interface{}) interface{} {
+	switch v := n.(type) {
+	case Walkable:
+		return v.WithChildren(cs)
+	case unsafeExpr:
+		return unsafeExpr(cs)
+	case inVariable:
+		return inVariable(cs)
+	case *variable:
+		return &variable{V: cs[0]}
+	case *aliasedVariable:
+		return &aliasedVariable{V: cs[0], Alias: v.Alias}
+	case *gtExpr:
+		return &gtExpr{Left: cs[0], Right: cs[1]}
+	case *gteExpr:
+		return &gteExpr{Left: cs[0], Right: cs[1]}
+	case *ltExpr:
+		return &ltExpr{Left: cs[0], Right: cs[1]}
+	case *lteExpr:
+		return &lteExpr{Left: cs[0], Right: cs[1]}
+	case *simpleInExpr:
+		return &simpleInExpr{Left: cs[0], Right: cs[1]}
+	case *simpleNotInExpr:
+		return &simpleNotInExpr{Left: cs[0], Right: cs[1]}
+	case *eqExpr:
+		return &eqExpr{Left: cs[0], Right: cs[1]}
+	case *neqExpr:
+		return &neqExpr{Left: cs[0], Right: cs[1]}
+	case *inExpr:
+		return &inExpr{Left: cs[0], Right: cs[1].(inVariable)}
+	case *notInExpr:
+		return &notInExpr{Left: cs[0], Right: cs[1].(inVariable)}
+	case ZAndExpr:
+		return ZAndExpr(nodesToExprs(cs))
+	case ZOrExpr:
+		return ZOrExpr(nodesToExprs(cs))
+	case *logicalExpr:
+		return &logicalExpr{And: v.And, Exprs: nodesToExprs(cs)}
+	case *notExpr:
+		return &notExpr{Expression: cs[0].(Expression)}
+	case *existsExpr:
+		return &existsExpr{Not: v.Not, Select: cs[0].(*ZSelectBuilder)}
+	case *quantifier:
+		return &quantifier{Name: v.Name, V: cs[0]}
+	case *quantifiedExpr:
+		return &quantifiedExpr{Left: cs[0], Op: v.Op, Right: cs[1].(*quantifier)}
+	case *likeExpr:
+		r := *v
+		r.Left, r.Right = cs[0], cs[1]
+		return &r
+	case *betweenExpr:
+		return &betweenExpr{V: cs[0], From: cs[1], To: cs[2], Not: v.Not}
+	case *nullSafeEqExpr:
+		return &nullSafeEqExpr{Left: cs[0], Right: cs[1], Not: v.Not}
+	case *tupleExpr:
+		return &tupleExpr{Values: cs}
+	case *seekExpr:
+		r := &seekExpr{Keys: make([]seekKey, len(v.Keys)), Err: v.Err}
+		for i, k := range v.Keys {
+			k.Expression, k.Value = nodeToExpr(cs[i*2]), cs[i*2+1]
+			r.Keys[i] = k
+		}
+		return r
+	case *rowCompareExpr:
+		return &rowCompareExpr{Op: v.Op, Left: cs[0].(*tupleExpr), Right: cs[1]}
+	case *tupleInExpr:
+		r := &tupleInExpr{Left: cs[0].(*tupleExpr), Not: v.Not}
+		if v.Right != nil {
+			r.Right = make([]*tupleExpr, len(v.Right))
+			for i := range r.Right {
+				r.Right[i] = cs[1+i].(*tupleExpr)
+			}
+		}
+		return r
+	case *distinctExpr:
+		return &distinctExpr{V: cs[0]}
+	case *rowNumberExpr:
+		r := &rowNumberExpr{Partitions: nodesToExprs(cs[:len(v.Partitions)])}
+		if v.Orders != nil {
+			r.Orders = make([]Ordering, len(v.Orders))
+			for i, o := range v.Orders {
+				o.Expression = nodeToExpr(cs[len(v.Partitions)+i])
+				r.Orders[i] = o
+			}
+		}
+		return r
+	case *groupingSetExpr:
+		r := &groupingSetExpr{Name: v.Name, Sets: make([][]Expression, len(v.Sets))}
+		for i, set := range v.Sets {
+			if set == nil {
+				continue
+			}
+			r.Sets[i] = make([]Expression, len(set))
+			for j := range set {
+				r.Sets[i][j] = cs[0].(Expression)
+				cs = cs[1:]
+			}
+		}
+		return r
+	case *binaryOpExpr:
+		return &binaryOpExpr{Op: v.Op, Left: cs[0], Right: cs[1]}
+	case *unaryOpExpr:
+		return &unaryOpExpr{Op: v.Op, V: cs[0]}
+	case *parenExpr:
+		// The parentheses are not needed if the child is replaced by a value.
+		if e, ok := cs[0].(Expression); ok {
+			return &parenExpr{e}
+		}
+		return cs[0]
+	case operandExpr:
+		return operandExpr{cs[0]}
+	case *arrayExpr:
+		if reflect.ValueOf(v.V).IsNil() {
+			return v
+		}
+		return &arrayExpr{V: nodesToSlice(reflect.TypeOf(v.V), cs)}
+	case *concatExpr:
+		return &concatExpr{Values: cs}
+	case *function:
+		return &function{Name: v.Name, V: cs[0]}
+	case *charLengthFunc:
+		return &charLengthFunc{V: cs[0]}
+	case *addIntervalFunc:
+		ivs := make([]qutil.Interval, len(v.Intervals))
+		n := 1
+		for i, iv := range v.Intervals {
+			if e, ok := iv.(*exprInterval); ok {
+				iv = &exprInterval{Q: cs[n], U: e.U, N: e.N}
+				n++
+			}
+			ivs[i] = iv
+		}
+		return &addIntervalFunc{V: cs[0], Intervals: ivs}
+	case *castFunc:
+		return &castFunc{V: cs[0], Type: v.Type}
+	case *dateTruncFunc:
+		return &dateTruncFunc{Unit: v.Unit, V: cs[0]}
+	case *extractFunc:
+		return &extractFunc{Unit: v.Unit, V: cs[0]}
+	case *dateDiffFunc:
+		return &dateDiffFunc{Unit: v.Unit, From: cs[0], To: cs[1]}
+	case *isoFunc:
+		return &isoFunc{Parse: v.Parse, V: cs[0]}
+	case *variadicFunc:
+		return &variadicFunc{Name: v.Name, Values: cs}
+	case *stringFunc:
+		return &stringFunc{Op: v.Op, Values: cs}
+	case *greatestFunc:
+		return &greatestFunc{Least: v.Least, Values: cs}
+	case *ifFunc:
+		return &ifFunc{Cond: cs[0], Then: cs[1], Else: cs[2]}
+	case *ZCaseBuilder:
+		r := &ZCaseBuilder{Base: nodeToExpr(cs[0]), ElseThen: nodeToExpr(cs[1])}
+		if v.WhenThen != nil {
+			r.WhenThen = make([][2]Expression, len(v.WhenThen))
+			for i := range r.WhenThen {
+				r.WhenThen[i] = [2]Expression{nodeToExpr(cs[2+i*2]), nodeToExpr(cs[3+i*2])}
+			}
+		}
+		return r
+	case *columnAlias:
+		return &columnAlias{Column: cs[0].(Column), Alias: v.Alias}
+	case *exprAsColumn:
+		return &exprAsColumn{Expression: cs[0].(Expression)}
+	case *table:
+		return &table{Table: v.Table, joinable: joinable{Joins: nodesToJoins(v.Joins, cs)}}
+	case *tableAlias:
+		return &tableAlias{Table: cs[0].(Table), Alias: v.Alias}
+	case *selectBuilderAsTable:
+		return &selectBuilderAsTable{
+			ZSelectBuilder: cs[0].(*ZSelectBuilder),
+			Alias:          v.Alias,
+			joinable:       joinable{Joins: nodesToJoins(v.Joins, cs[1:])},
+		}
+	case *ZSelectBuilder:
+		r := *v
+		if v.DistinctOns != nil {
+			r.DistinctOns = nodesToExprs(cs[:len(v.DistinctOns)])
+		}
+		cs = cs[len(v.DistinctOns):]
+		if v.Columns != nil {
+			r.Columns = make([]Column, len(v.Columns))
+			for i := range r.Columns {
+				r.Columns[i] = cs[i].(Column)
+			}
+		}
+		cs = cs[len(v.Columns):]
+		if v.Tables != nil {
+			r.Tables = make([]Table, len(v.Tables))
+			for i := range r.Tables {
+				r.Tables[i] = cs[i].(Table)
+			}
+		}
+		cs = cs[len(v.Tables):]
+		r.Wheres = ZAndExpr(nodesToExprs(cs[:len(v.Wheres)]))
+		cs = cs[len(v.Wheres):]
+		if v.Groups != nil {
+			r.Groups = nodesToExprs(cs[:len(v.Groups)])
+		}
+		cs = cs[len(v.Groups):]
+		r.Havings = ZAndExpr(nodesToExprs(cs[:len(v.Havings)]))
+		cs = cs[len(v.Havings):]
+		if v.Orders != nil {
+			r.Orders = make([]Ordering, len(v.Orders))
+			for i, o := range v.Orders {
+				o.Expression = nodeToExpr(cs[i])
+				r.Orders[i] = o
+			}
+		}
+		cs = cs[len(v.Orders):]
+		r.LimitCount, r.StartOffset = nodeToExpr(cs[0]), nodeToExpr(cs[1])
+		return &r
+	case *ZInsertBuilder:
+		r := *v
+		r.Table = cs[0].(Table)
+		cs = cs[1:]
+		if v.Sets != nil {
+			r.Sets = make([]struct {
+				Name string
+				Column
+				Expression
+			}, len(v.Sets))
+			for i := range r.Sets {
+				c := cs[i*2].(Column)
+				r.Sets[i].Name, r.Sets[i].Column, r.Sets[i].Expression = cudColumnName(c), c, nodeToExpr(cs[i*2+1])
+			}
+		}
+		cs = cs[len(v.Sets)*2:]
+		if v.Returnings != nil {
+			r.Returnings = make([]Column, len(v.Returnings))
+			for i := range r.Returnings {
+				r.Returnings[i] = cs[i].(Column)
+			}
+		}
+		return &r
+	case *ZUpdateBuilder:
+		r := *v
+		r.Table = cs[0].(Table)
+		cs = cs[1:]
+		if v.Sets != nil {
+			r.Sets = make([]struct {
+				Name string
+				Column
+				Expression
+			}, len(v.Sets))
+			for i := range r.Sets {
+				c := cs[i*2].(Column)
+				r.Sets[i].Name, r.Sets[i].Column, r.Sets[i].Expression = cudColumnName(c), c, nodeToExpr(cs[i*2+1])
+			}
+		}
+		r.Wheres = ZAndExpr(nodesToExprs(cs[len(v.Sets)*2:]))
+		return &r
+	case *ZDeleteBuilder:
+		r := *v
+		if cs[0] != nil {
+			r.Table = cs[0].(Table)
+		}
+		r.Wheres = ZAndExpr(nodesToExprs(cs[1:]))
+		return &r
+	}
+	return n
+}
+
+// Walk traverses n in depth-first order.
+// It calls fn for n and then for each child node of n if fn returns true.
+// nil nodes are skipped.
+func Walk(n interface{}, fn func(n interface{}) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for _, c := range Children(n) {
+		Walk(c, fn)
+	}
+}
+
+// Transform returns a copy of n which is rewritten by fn.
+//
+// fn is called in depth-first order, for the child nodes first and then for the parent node
+// that is already rebuilt with the rewritten child nodes.
+// The returned value of fn replaces the node,
+// so it must be assignable to the place of the original node.
+// n itself is never modified. nil nodes are kept as is without calling fn.
+func Transform(n interface{}, fn func(n interface{}) interface{}) interface{} {
+	if n == nil {
+		return nil
+	}
+	if cs := Children(n); cs != nil {
+		ncs := make([]interface{}, len(cs))
+		for i, c := range cs {
+			ncs[i] = Transform(c, fn)
+		}
+		n = withChildren(n, ncs)
+	}
+	return fn(n)
+}
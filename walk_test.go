@@ -0,0 +1,224 @@
+package q
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oov/q/qutil"
+)
+
+func TestWalk(t *testing.T) {
+	user, post := T("user", "u"), T("post", "p")
+	tenantID := user.C("tenant_id")
+	sel := Select().From(
+		user.InnerJoin(post, Eq(post.C("user_id"), user.C("id"))),
+	).Column(
+		user.C("name"),
+		Case(user.C("age")).When(15, "young").Else("old").C("generation"),
+	).Where(
+		Or(Eq(tenantID, 1), In(user.C("id"), []int{1, 2})),
+		Gt(CharLength(user.C("name")), 3),
+	).OrderBy(user.C("id"), true).Limit(10)
+
+	var found []string
+	Walk(sel, func(n interface{}) bool {
+		if c, ok := n.(Column); ok && c == tenantID {
+			found = append(found, fmt.Sprint(c))
+		}
+		return true
+	})
+	if want, got := `["u"."tenant_id" []]`, fmt.Sprint(found); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	var values []interface{}
+	Walk(sel.Wheres, func(n interface{}) bool {
+		switch n.(type) {
+		case Expression, Column, Table:
+		default:
+			values = append(values, n)
+		}
+		return true
+	})
+	if want, got := `[1 1 2 3]`, fmt.Sprint(values); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	var visited int
+	Walk(sel, func(n interface{}) bool {
+		visited++
+		_, isCase := n.(*ZCaseBuilder)
+		return !isCase
+	})
+	var all int
+	Walk(sel, func(n interface{}) bool {
+		all++
+		return true
+	})
+	if all-visited != 7 {
+		t.Errorf("want 7 skipped nodes got %d", all-visited)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	tests := []struct {
+		N    interface{}
+		Want int
+	}{
+		{N: C("id"), Want: 0},
+		{N: T("user").C("id"), Want: 0},
+		{N: C("id", "i"), Want: 1},
+		{N: Eq(C("id"), 1), Want: 2},
+		{N: And(Eq(C("id"), 1), Eq(C("id"), 2), Eq(C("id"), 3)), Want: 3},
+		{N: InV([]int{1, 2}), Want: 2},
+		{N: Unsafe(C("id"), " = ", 1), Want: 3},
+		{N: Case().When(true, 1).When(false, 2), Want: 6},
+		{N: T("user").InnerJoin(T("post"), Eq(1, 1), Eq(2, 2)), Want: 3},
+		{N: Select().From(T("user")).Where(Eq(C("id"), 1)).Column(C("id")), Want: 5},
+		{N: Insert().Into(T("user")).Set(C("id"), 1).Returning(C("id")), Want: 4},
+		{N: Update(T("user")).Set(C("id"), 1).Where(Eq(C("id"), 2)), Want: 4},
+		{N: Delete(T("user")).Where(Eq(C("id"), 2)), Want: 2},
+	}
+	for i, test := range tests {
+		if got := len(Children(test.N)); got != test.Want {
+			t.Errorf("tests[%d] %v want %d children got %d", i, test.N, test.Want, got)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	user := T("user")
+	sel := Select().From(user).Column(user.C("id"), user.C("name", "n")).Where(
+		Eq(user.C("deleted"), false),
+		In(user.C("id"), []int{1, 2}),
+	).GroupBy(user.C("id")).OrderBy(user.C("id"), false)
+	before := sel.String()
+
+	r := Transform(sel, func(n interface{}) interface{} {
+		if v, ok := n.(int); ok {
+			return v * 10
+		}
+		if c, ok := n.(Column); ok && fmt.Sprint(c) == `"user"."deleted" []` {
+			return user.C("removed")
+		}
+		return n
+	}).(*ZSelectBuilder)
+
+	if got := sel.String(); got != before {
+		t.Errorf("the original builder was modified: %s", got)
+	}
+	want := `SELECT "user"."id", "user"."name" AS "n" FROM "user" WHERE ("user"."removed" = ?)AND("user"."id" IN (?,?)) GROUP BY "user"."id" ORDER BY "user"."id" DESC [false 10 20]`
+	if got := r.String(); got != want {
+		t.Errorf("want %s got %s", want, got)
+	}
+
+	for i, b := range []Builder{
+		Insert().Into(user).Set(C("id"), 1).Set(C("name"), "a").Returning(C("id")),
+		Update(user).Set(C("id"), 1).Where(Eq(C("id"), 2)),
+		Delete(user).Where(Eq(C("id"), 2)),
+		Select().From(Select().From(user.InnerJoin(T("post"), Eq(1, 1))).T("s")).Column(Case().When(true, 1).Else(2).C("c")),
+	} {
+		r := Transform(b, func(n interface{}) interface{} { return n }).(Builder)
+		if r == b {
+			t.Errorf("tests[%d] want a copy", i)
+		}
+		if want, got := b.String(), r.String(); want != got {
+			t.Errorf("tests[%d] want %s got %s", i, want, got)
+		}
+	}
+}
+
+func TestTransformParen(t *testing.T) {
+	n := C("n")
+	e := AddInterval(C("at"), IntervalOf(Add(n, 1), Day))
+
+	found := false
+	Walk(e, func(x interface{}) bool {
+		if x == n {
+			found = true
+		}
+		return true
+	})
+	if !found {
+		t.Errorf("Walk doesn't visit the column in parentheses")
+	}
+
+	r := Transform(e, func(x interface{}) interface{} {
+		if x == n {
+			return C("m")
+		}
+		return x
+	})
+	if want, got := `"at" + INTERVAL ("m" + ?) DAY [1]`, fmt.Sprint(r); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+type upperExpr struct {
+	V interface{}
+}
+
+func (e *upperExpr) C(aliasName ...string) Column { return columnExpr(e, aliasName...) }
+func (e *upperExpr) WriteExpression(ctx *qutil.Context, buf []byte) []byte {
+	buf = append(buf, "UPPER("...)
+	buf = writeIntf(e.V, ctx, buf)
+	return append(buf, ')')
+}
+func (e *upperExpr) Children() []interface{}                         { return []interface{}{e.V} }
+func (e *upperExpr) WithChildren(children []interface{}) interface{} { return &upperExpr{children[0]} }
+
+func TestWalkable(t *testing.T) {
+	e := Eq(&upperExpr{C("name")}, "A")
+	var found bool
+	Walk(e, func(n interface{}) bool {
+		found = found || n == C("name")
+		return true
+	})
+	if !found {
+		t.Errorf("want to find the column in the custom expression")
+	}
+
+	r := Transform(e, func(n interface{}) interface{} {
+		if n == C("name") {
+			return C("nick")
+		}
+		return n
+	})
+	if want, got := `UPPER("nick") = ? [A]`, fmt.Sprint(r); want != got {
+		t.Errorf("want %s got %s", want, got)
+	}
+}
+
+func TestTransformArray(t *testing.T) {
+	e := Eq(C("id"), Any(Array([]int{1, 2})))
+	var found bool
+	Walk(e, func(n interface{}) bool {
+		found = found || n == 2
+		return true
+	})
+	if !found {
+		t.Errorf("want to find the element of the array")
+	}
+
+	for i, test := range []struct {
+		To   interface{}
+		Want string
+	}{
+		{3, `"id" = ANY ($1) [{1,3}]`},
+		{"a", `"id" = ANY ($1) [{1,"a"}]`},
+	} {
+		r := Transform(e, func(n interface{}) interface{} {
+			if n == 2 {
+				return test.To
+			}
+			return n
+		}).(Expression)
+		sql, args := Select().From(T("t")).Where(r).SetDialect(PostgreSQL).ToSQL()
+		if got, want := sql[len(`SELECT * FROM "t" WHERE `):]+" "+fmt.Sprint(args), test.Want; want != got {
+			t.Errorf("tests[%d] want %s got %s", i, want, got)
+		}
+	}
+	if want, got := `"id" = ANY (?) [{1,2}]`, fmt.Sprint(clone(e)); want != got {
+		t.Errorf("clone want %s got %s", want, got)
+	}
+}